@@ -0,0 +1,58 @@
+package yaml
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ExpandAliases resolves all anchors, aliases and merge keys ("<<") in the
+// YAML document doc and returns an equivalent document without them.
+// Mapping keys keep their document order; keys brought in by a merge key are
+// placed after the mapping's own keys.
+//
+// The document is re-emitted from its decoded form, so its comments and
+// scalar styles are lost. A stream of several documents is rejected rather
+// than cut down to the first one; use SplitDocuments to expand each of them.
+func ExpandAliases(doc []byte) ([]byte, error) {
+	if err := checkSingleDocument(doc); err != nil {
+		return nil, err
+	}
+	obj, err := yamlUnmarshalOrdered(doc, yaml.Unmarshal)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(obj)
+}
+
+// DisallowDuplicateAnchors makes UnmarshalWithOptions reject documents that
+// define the same anchor twice. YAML allows it, with aliases referring to
+// the latest definition, but in configuration files it is almost always a
+// copy-and-paste mistake.
+func DisallowDuplicateAnchors() Option {
+	return func(o *options) {
+		o.disallowDuplicateAnchors = true
+	}
+}
+
+// checkDuplicateAnchors returns an error locating both definitions of the
+// first anchor defined twice in a document of the YAML stream y.
+func checkDuplicateAnchors(y []byte) error {
+	type position struct{ line, column int }
+	defined := map[string]position{}
+	for _, t := range scanTokens(y) {
+		switch t.kind {
+		case documentToken:
+			// Anchors are scoped to their document.
+			defined = map[string]position{}
+		case anchorToken:
+			name := string(y[t.start+1 : t.end])
+			if p, ok := defined[name]; ok {
+				return fmt.Errorf("yaml: line %d, column %d: anchor %q is already defined at line %d, column %d",
+					t.line, t.column, name, p.line, p.column)
+			}
+			defined[name] = position{t.line, t.column}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package yaml
+
+import (
+	"testing"
+)
+
+func TestExpandAliases(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "no aliases",
+			input: "z: 1\na: 2\n",
+			want:  "z: 1\na: 2\n",
+		},
+		{
+			name: "alias",
+			input: `
+base: &base
+  z: 1
+  a: [p, q]
+copy: *base
+`,
+			want: "base:\n  z: 1\n  a:\n  - p\n  - q\ncopy:\n  z: 1\n  a:\n  - p\n  - q\n",
+		},
+		{
+			name: "merge keys",
+			input: `
+defaults: &defaults
+  b: 1
+  a: 2
+svc:
+  <<: *defaults
+  name: web
+  a: 3
+`,
+			want: "defaults:\n  b: 1\n  a: 2\nsvc:\n  name: web\n  a: 3\n  b: 1\n",
+		},
+		{
+			name: "merge list in sequence",
+			input: `
+- &x {k: 1}
+- &y {j: 2}
+- <<: [*x, *y]
+  i: 3
+`,
+			want: "- k: 1\n- j: 2\n- i: 3\n  j: 2\n  k: 1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExpandAliases([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("ExpandAliases() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ExpandAliases([]byte("a: *missing\n")); err == nil {
+		t.Error("expected error for unknown alias")
+	}
+
+	_, err := ExpandAliases([]byte("a: &x 1\n---\nb: *x\n"))
+	if want := "yaml: line 2: found a document after the first one, expected a single document"; err == nil || err.Error() != want {
+		t.Errorf("ExpandAliases() error = %v, want %q", err, want)
+	}
+	got, err := ExpandAliases([]byte("a: &x 1\nb: *x\n---\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "a: 1\nb: 1\n"; string(got) != want {
+		t.Errorf("ExpandAliases() = %q, want %q", got, want)
+	}
+}
+
+func TestDisallowDuplicateAnchors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "distinct anchors",
+			input: "a: &x 1\nb: &z 2\nc: *x\n",
+		},
+		{
+			name:    "redefined anchor",
+			input:   "a: &x 1\nb:\n  c: &x 2\nd: *x\n",
+			wantErr: `yaml: line 3, column 6: anchor "x" is already defined at line 1, column 4`,
+		},
+		{
+			name:  "separate documents",
+			input: "a: &x 1\n---\nb: &x 2\n",
+		},
+		{
+			name:    "redefined anchor in JSON syntax",
+			input:   `{"a":&x [1],"b":{"c":&x 2},"d":*x}`,
+			wantErr: `yaml: line 1, column 22: anchor "x" is already defined at line 1, column 6`,
+		},
+		{
+			name:    "redefined anchor in flow sequence",
+			input:   "a: [&x 1,&x 2]\n",
+			wantErr: `yaml: line 1, column 10: anchor "x" is already defined at line 1, column 5`,
+		},
+		{
+			name:  "anchor-like text",
+			input: "a: '&x'\nb: '&x'\nc: \"a &x\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			if err := UnmarshalWithOptions([]byte(tt.input), &v); err != nil {
+				t.Fatalf("unexpected error without the option: %v", err)
+			}
+			err := UnmarshalWithOptions([]byte(tt.input), &v, DisallowDuplicateAnchors())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+			} else if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("got error %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
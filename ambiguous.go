@@ -0,0 +1,227 @@
+package yaml
+
+import (
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// AmbiguousScalar is a plain scalar that YAML 1.1 and YAML 1.2 parsers
+// resolve to different values, such as "on", "no", "010" or "1:30".
+type AmbiguousScalar struct {
+	// Value is the scalar as written.
+	Value string
+	// Line and Column locate the scalar in the document, 1-based.
+	Line   int
+	Column int
+	// YAML11 and YAML12 are the values the scalar resolves to under YAML 1.1
+	// and under the YAML 1.2 core schema: nil, a bool, an int64, a float64,
+	// a time.Time or a string.
+	YAML11 interface{}
+	YAML12 interface{}
+}
+
+// FindAmbiguousScalars reports the plain scalars of the YAML stream doc,
+// keys included, whose value depends on whether it is read as YAML 1.1 or
+// YAML 1.2. Scalars with an explicit tag are not reported. It is meant as a
+// pre-flight check before switching a consumer to a different parser.
+func FindAmbiguousScalars(doc []byte) ([]AmbiguousScalar, error) {
+	if _, err := yamlUnmarshalAll(doc); err != nil {
+		return nil, err
+	}
+
+	var found []AmbiguousScalar
+	tokens := scanTokens(doc)
+	for i, t := range tokens {
+		if t.kind != plainToken || t.multiline {
+			continue
+		}
+		if i > 0 && tokens[i-1].kind == tagToken && tokens[i-1].line == t.line &&
+			strings.TrimSpace(string(doc[tokens[i-1].end:t.start])) == "" {
+			continue
+		}
+		value := string(doc[t.start:t.end])
+		v11, v12 := resolveYAML11(value), resolveYAML12(value)
+		if sameResolution(v11, v12) {
+			continue
+		}
+		found = append(found, AmbiguousScalar{
+			Value:  value,
+			Line:   t.line,
+			Column: t.column,
+			YAML11: v11,
+			YAML12: v12,
+		})
+	}
+	return found, nil
+}
+
+func sameResolution(a, b interface{}) bool {
+	if fa, ok := a.(float64); ok && math.IsNaN(fa) {
+		fb, ok := b.(float64)
+		return ok && math.IsNaN(fb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// The regular expressions below follow the type definitions of the YAML 1.1
+// tag repository (yaml.org/type) and the YAML 1.2 core schema.
+var (
+	yaml11Bool = map[string]bool{
+		"y": true, "Y": true, "yes": true, "Yes": true, "YES": true,
+		"true": true, "True": true, "TRUE": true, "on": true, "On": true, "ON": true,
+		"n": false, "N": false, "no": false, "No": false, "NO": false,
+		"false": false, "False": false, "FALSE": false, "off": false, "Off": false, "OFF": false,
+	}
+	yaml11Int         = regexp.MustCompile(`^[-+]?(0b[0-1_]+|0[0-7_]+|(0|[1-9][0-9_]*)|0x[0-9a-fA-F_]+)$`)
+	yaml11Sexagesimal = regexp.MustCompile(`^[-+]?[1-9][0-9_]*(:[0-5]?[0-9])+(\.[0-9_]*)?$`)
+	yaml11Float       = regexp.MustCompile(`^[-+]?([0-9][0-9_]*)?\.[0-9.]*([eE][-+][0-9]+)?$`)
+	yaml11Timestamp   = regexp.MustCompile(`^[0-9][0-9][0-9][0-9]-[0-9][0-9]?-[0-9][0-9]?` +
+		`(([Tt]|[ \t]+)[0-9][0-9]?:[0-9][0-9]:[0-9][0-9](\.[0-9]*)?` +
+		`(([ \t]*)Z|[-+][0-9][0-9]?(:[0-9][0-9])?)?)?$`)
+
+	yaml12Int   = regexp.MustCompile(`^([-+]?[0-9]+|0o[0-7]+|0x[0-9a-fA-F]+)$`)
+	yaml12Float = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+)
+
+// resolveSpecialFloat resolves the infinity and not-a-number spellings
+// shared by YAML 1.1 and 1.2.
+func resolveSpecialFloat(s string) (float64, bool) {
+	switch s {
+	case ".inf", ".Inf", ".INF", "+.inf", "+.Inf", "+.INF":
+		return math.Inf(1), true
+	case "-.inf", "-.Inf", "-.INF":
+		return math.Inf(-1), true
+	case ".nan", ".NaN", ".NAN":
+		return math.NaN(), true
+	}
+	return 0, false
+}
+
+// resolveYAML11 resolves a plain scalar following YAML 1.1.
+func resolveYAML11(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	}
+	if b, ok := yaml11Bool[s]; ok {
+		return b
+	}
+	if f, ok := resolveSpecialFloat(s); ok {
+		return f
+	}
+	if yaml11Int.MatchString(s) {
+		plain := strings.Replace(s, "_", "", -1)
+		sign := int64(1)
+		switch plain[0] {
+		case '-':
+			sign = -1
+			plain = plain[1:]
+		case '+':
+			plain = plain[1:]
+		}
+		base := 10
+		switch {
+		case strings.HasPrefix(plain, "0b"):
+			base, plain = 2, plain[2:]
+		case strings.HasPrefix(plain, "0x"):
+			base, plain = 16, plain[2:]
+		case len(plain) > 1 && plain[0] == '0':
+			base = 8
+		}
+		if i, err := strconv.ParseInt(plain, base, 64); err == nil {
+			return sign * i
+		}
+		return s
+	}
+	if yaml11Sexagesimal.MatchString(s) {
+		plain := strings.Replace(s, "_", "", -1)
+		sign := 1.0
+		switch plain[0] {
+		case '-':
+			sign = -1
+			plain = plain[1:]
+		case '+':
+			plain = plain[1:]
+		}
+		var v float64
+		for _, part := range strings.Split(plain, ":") {
+			f, err := strconv.ParseFloat(part, 64)
+			if err != nil {
+				return s
+			}
+			v = v*60 + f
+		}
+		if strings.Contains(plain, ".") {
+			return sign * v
+		}
+		return int64(sign * v)
+	}
+	if yaml11Float.MatchString(s) {
+		if f, err := strconv.ParseFloat(strings.Replace(s, "_", "", -1), 64); err == nil {
+			return f
+		}
+		return s
+	}
+	if yaml11Timestamp.MatchString(s) {
+		if t, ok := parseTimestamp(s); ok {
+			return t
+		}
+	}
+	return s
+}
+
+// resolveYAML12 resolves a plain scalar following the YAML 1.2 core schema.
+func resolveYAML12(s string) interface{} {
+	switch s {
+	case "", "~", "null", "Null", "NULL":
+		return nil
+	case "true", "True", "TRUE":
+		return true
+	case "false", "False", "FALSE":
+		return false
+	}
+	if f, ok := resolveSpecialFloat(s); ok {
+		return f
+	}
+	if yaml12Int.MatchString(s) {
+		var i int64
+		var err error
+		switch {
+		case strings.HasPrefix(s, "0o"):
+			i, err = strconv.ParseInt(s[2:], 8, 64)
+		case strings.HasPrefix(s, "0x"):
+			i, err = strconv.ParseInt(s[2:], 16, 64)
+		default:
+			i, err = strconv.ParseInt(s, 10, 64)
+		}
+		if err == nil {
+			return i
+		}
+		return s
+	}
+	if yaml12Float.MatchString(s) {
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f
+		}
+	}
+	return s
+}
+
+// parseTimestamp parses the YAML 1.1 timestamp formats.
+func parseTimestamp(s string) (time.Time, bool) {
+	for _, layout := range []string{
+		"2006-1-2T15:4:5.999999999Z07:00",
+		"2006-1-2t15:4:5.999999999Z07:00",
+		"2006-1-2 15:4:5.999999999",
+		"2006-1-2",
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,52 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFindAmbiguousScalars(t *testing.T) {
+	y := []byte(`enabled: on
+country: NO
+mode: 0644
+build: 08
+duration: 1:30
+size: 1_000
+exp: 1e3
+date: 2024-01-01
+octal: 0o17
+NO: key
+quoted: "on"
+tagged: !!str off
+flow: [yes, 'no', 12]
+name: plain text
+description: a long
+  on
+version: 1.20
+`)
+	got, err := FindAmbiguousScalars(y)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []AmbiguousScalar{
+		{Value: "on", Line: 1, Column: 10, YAML11: true, YAML12: "on"},
+		{Value: "NO", Line: 2, Column: 10, YAML11: false, YAML12: "NO"},
+		{Value: "0644", Line: 3, Column: 7, YAML11: int64(420), YAML12: int64(644)},
+		{Value: "08", Line: 4, Column: 8, YAML11: "08", YAML12: int64(8)},
+		{Value: "1:30", Line: 5, Column: 11, YAML11: int64(90), YAML12: "1:30"},
+		{Value: "1_000", Line: 6, Column: 7, YAML11: int64(1000), YAML12: "1_000"},
+		{Value: "1e3", Line: 7, Column: 6, YAML11: "1e3", YAML12: float64(1000)},
+		{Value: "2024-01-01", Line: 8, Column: 7, YAML11: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), YAML12: "2024-01-01"},
+		{Value: "0o17", Line: 9, Column: 8, YAML11: "0o17", YAML12: int64(15)},
+		{Value: "NO", Line: 10, Column: 1, YAML11: false, YAML12: "NO"},
+		{Value: "yes", Line: 13, Column: 8, YAML11: true, YAML12: "yes"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindAmbiguousScalars() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if _, err := FindAmbiguousScalars([]byte("a: [")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
@@ -0,0 +1,168 @@
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// numberMode selects the Go type of numbers decoded by UnmarshalAny.
+type numberMode int
+
+const (
+	numbersAsFloat64 numberMode = iota
+	numbersAsJSONNumber
+	numbersAsInt64
+)
+
+// OrderedMaps makes UnmarshalAny decode mappings into yaml.MapSlices, which
+// keep the keys in document order, instead of map[string]interface{}. Keys
+// brought in by merge keys ("<<") follow the mapping's own keys.
+//
+// It also makes YAMLToJSONWithOptions write the members of JSON objects in
+// document order rather than sorted, so that textual diffs of converted
+// files stay stable and reviewable. Members brought in by merge keys follow
+// the mapping's own members, sorted.
+func OrderedMaps() Option {
+	return func(o *options) {
+		o.orderedMaps = true
+	}
+}
+
+// UseNumber makes UnmarshalAny decode numbers into json.Numbers, which keep
+// the digits of integers too large for a float64.
+func UseNumber() Option {
+	return func(o *options) {
+		o.numbers = numbersAsJSONNumber
+	}
+}
+
+// UseInt64 makes UnmarshalAny decode integers into int64s, and other numbers
+// into float64s. Integers that do not fit in an int64 become float64s.
+func UseInt64() Option {
+	return func(o *options) {
+		o.numbers = numbersAsInt64
+	}
+}
+
+// UnmarshalAny decodes the first YAML document in data into the values
+// Unmarshal would produce when decoding into an interface{}: mappings become
+// map[string]interface{}, sequences []interface{} and numbers float64.
+// OrderedMaps, UseNumber and UseInt64 choose other types for mappings and
+// numbers. Infinities and NaN, which have no JSON representation, are always
+// float64s.
+//
+// The input options of UnmarshalWithOptions, such as Strict and
+// SingleDocument, apply as well.
+func UnmarshalAny(data []byte, opts ...Option) (interface{}, error) {
+	c := &converter{fields: cachedStructFields, opts: newOptions(opts...)}
+	v, err := c.unmarshalAny(data)
+	if err != nil {
+		return nil, c.opts.sourceError(err)
+	}
+	return v, nil
+}
+
+func (c *converter) unmarshalAny(data []byte) (interface{}, error) {
+	data, err := c.checkInput(data)
+	if err != nil {
+		return nil, err
+	}
+	unmarshalFn := yaml.Unmarshal
+	if c.opts.strict {
+		unmarshalFn = yaml.UnmarshalStrict
+	}
+
+	var obj interface{}
+	if c.opts.orderedMaps {
+		obj, err = yamlUnmarshalOrdered(data, unmarshalFn)
+	} else {
+		err = unmarshalFn(data, &obj)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error converting YAML to JSON: %v", err)
+	}
+	if c.opts.maxDepth > 0 {
+		if err := checkDepth(obj, c.opts.maxDepth); err != nil {
+			return nil, err
+		}
+	}
+	return c.anyValue(obj)
+}
+
+// anyValue converts a value decoded by go-yaml into the types selected by
+// the options of c.
+func (c *converter) anyValue(v interface{}) (interface{}, error) {
+	var err error
+	switch v := v.(type) {
+	case yaml.MapSlice:
+		ms := make(yaml.MapSlice, len(v))
+		for i, item := range v {
+			k, ok := keyToString(item.Key)
+			if !ok {
+				return nil, fmt.Errorf("Unsupported map key of type: %s, key: %+#v",
+					reflect.TypeOf(item.Key), item.Key)
+			}
+			ms[i].Key = k
+			if ms[i].Value, err = c.anyValue(item.Value); err != nil {
+				return nil, err
+			}
+		}
+		return ms, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			k, ok := keyToString(key)
+			if !ok {
+				return nil, fmt.Errorf("Unsupported map key of type: %s, key: %+#v",
+					reflect.TypeOf(key), key)
+			}
+			if m[k], err = c.anyValue(value); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case []interface{}:
+		a := make([]interface{}, len(v))
+		for i := range v {
+			if a[i], err = c.anyValue(v[i]); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	case int:
+		return c.anyInt(int64(v), strconv.Itoa(v)), nil
+	case int64:
+		return c.anyInt(v, strconv.FormatInt(v, 10)), nil
+	case uint64:
+		if v <= math.MaxInt64 {
+			return c.anyInt(int64(v), strconv.FormatUint(v, 10)), nil
+		}
+		if c.opts.numbers == numbersAsJSONNumber {
+			return json.Number(strconv.FormatUint(v, 10)), nil
+		}
+		return float64(v), nil
+	case float64:
+		if c.opts.numbers == numbersAsJSONNumber && !math.IsInf(v, 0) && !math.IsNaN(v) {
+			return json.Number(strconv.FormatFloat(v, 'g', -1, 64)), nil
+		}
+		return v, nil
+	}
+	return v, nil
+}
+
+// anyInt returns the integer i, written s, in the type selected by the
+// options of c.
+func (c *converter) anyInt(i int64, s string) interface{} {
+	switch c.opts.numbers {
+	case numbersAsJSONNumber:
+		return json.Number(s)
+	case numbersAsInt64:
+		return i
+	}
+	return float64(i)
+}
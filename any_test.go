@@ -0,0 +1,179 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestUnmarshalAny(t *testing.T) {
+	input := []byte(`b: 1
+a:
+  big: 18446744073709551615
+  f: 1.5
+  '2': [3, x]
+base: &base {p: 1, q: 2}
+c:
+  <<: *base
+  x: 3
+`)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want interface{}
+	}{
+		{
+			name: "default",
+			want: map[string]interface{}{
+				"b": float64(1),
+				"a": map[string]interface{}{
+					"big": float64(18446744073709551615),
+					"f":   1.5,
+					"2":   []interface{}{float64(3), "x"},
+				},
+				"base": map[string]interface{}{"p": float64(1), "q": float64(2)},
+				"c":    map[string]interface{}{"x": float64(3), "p": float64(1), "q": float64(2)},
+			},
+		},
+		{
+			name: "ordered maps and json.Number",
+			opts: []Option{OrderedMaps(), UseNumber()},
+			want: yaml.MapSlice{
+				{Key: "b", Value: json.Number("1")},
+				{Key: "a", Value: yaml.MapSlice{
+					{Key: "big", Value: json.Number("18446744073709551615")},
+					{Key: "f", Value: json.Number("1.5")},
+					{Key: "2", Value: []interface{}{json.Number("3"), "x"}},
+				}},
+				{Key: "base", Value: yaml.MapSlice{{Key: "p", Value: json.Number("1")}, {Key: "q", Value: json.Number("2")}}},
+				{Key: "c", Value: yaml.MapSlice{
+					{Key: "x", Value: json.Number("3")},
+					{Key: "p", Value: json.Number("1")},
+					{Key: "q", Value: json.Number("2")},
+				}},
+			},
+		},
+		{
+			name: "int64",
+			opts: []Option{UseInt64()},
+			want: map[string]interface{}{
+				"b": int64(1),
+				"a": map[string]interface{}{
+					"big": float64(18446744073709551615),
+					"f":   1.5,
+					"2":   []interface{}{int64(3), "x"},
+				},
+				"base": map[string]interface{}{"p": int64(1), "q": int64(2)},
+				"c":    map[string]interface{}{"x": int64(3), "p": int64(1), "q": int64(2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalAny(input, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UnmarshalAny() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+
+	got, err := UnmarshalAny([]byte("[.inf, .nan]"), UseNumber())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if a, ok := got.([]interface{}); !ok || len(a) != 2 || a[0] != math.Inf(1) || !math.IsNaN(a[1].(float64)) {
+		t.Errorf("UnmarshalAny() = %#v, want [+Inf NaN]", got)
+	}
+
+	if _, err := UnmarshalAny([]byte("a: 1\na: 2\n"), Strict()); err == nil {
+		t.Error("expected error for duplicate keys with Strict")
+	}
+}
+
+func TestYAMLToJSONOrderedMaps(t *testing.T) {
+	input := []byte(`zeta: 1
+alpha:
+  - name: web
+    image: nginx
+    ports: [{port: 80, name: http}]
+  - b
+base: &base {q: 1, p: 2}
+merged:
+  <<: *base
+  w: 3
+1e3: 1e9
+`)
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "sorted",
+			want: `{"1000":1000000000,"alpha":[{"image":"nginx","name":"web","ports":[{"name":"http","port":80}]},"b"],"base":{"p":2,"q":1},"merged":{"p":2,"q":1,"w":3},"zeta":1}`,
+		},
+		{
+			name: "ordered",
+			opts: []Option{OrderedMaps()},
+			want: `{"zeta":1,"alpha":[{"name":"web","image":"nginx","ports":[{"port":80,"name":"http"}]},"b"],"base":{"q":1,"p":2},"merged":{"w":3,"p":2,"q":1},"1000":1000000000}`,
+		},
+		{
+			name: "ordered with float format",
+			opts: []Option{OrderedMaps(), FormatFloats(DecimalFloatFormat)},
+			want: `{"zeta":1,"alpha":[{"name":"web","image":"nginx","ports":[{"port":80,"name":"http"}]},"b"],"base":{"q":1,"p":2},"merged":{"w":3,"p":2,"q":1},"1000":1000000000}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := YAMLToJSONWithOptions(input, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	got, err := YAMLToJSONWithOptions([]byte("- {b: 1, a: 2}\n- [ {d: 1, c: 2} ]\n"), OrderedMaps())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `[{"b":1,"a":2},[{"d":1,"c":2}]]`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+
+	// A duplicate key keeps its first position and its last value, whose
+	// members keep their own order.
+	got, err = YAMLToJSONWithOptions([]byte("b: {z: 1, x: 2}\na: 1\nb: {x: 3, z: 4}\n"), OrderedMaps())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"b":{"x":3,"z":4},"a":1}`; string(got) != want {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func BenchmarkYAMLToJSONOrderedMaps(b *testing.B) {
+	var buf bytes.Buffer
+	for i := 0; i < 40000; i++ {
+		fmt.Fprintf(&buf, "k%d: %d\n", i, i)
+	}
+	y := buf.Bytes()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := YAMLToJSONWithOptions(y, OrderedMaps()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// MapBuilder builds a YAML mapping from paths and values, keeping keys in
+// the order they are first set and attaching comments to entries, for
+// programs that generate documents without defining types for them:
+//
+//	y, err := yaml.NewMapBuilder().
+//		Set("apiVersion", "v1").
+//		Set("kind", "ConfigMap").
+//		Set("metadata.name", "settings").
+//		SetComment("metadata.name", "Referenced by the deployment.").
+//		Set("data.mode", "fast").
+//		Marshal()
+//
+// The methods of a MapBuilder return it, for chaining. The first error they
+// meet, such as setting a key within a scalar, is returned by Marshal.
+type MapBuilder struct {
+	root     yaml.MapSlice
+	comments map[string]string
+	err      error
+}
+
+// NewMapBuilder returns an empty MapBuilder.
+func NewMapBuilder() *MapBuilder {
+	return &MapBuilder{comments: map[string]string{}}
+}
+
+// Set sets the value at path, creating the mappings and sequences on the
+// way. Paths join mapping keys with "." and append "[i]" for sequence
+// entries, as in "spec.ports[0].port"; an index one past the end of a
+// sequence appends to it. value is converted as Marshal would, through its
+// JSON encoding, except that yaml.MapSlices keep their order.
+func (b *MapBuilder) Set(path string, value interface{}) *MapBuilder {
+	if b.err != nil {
+		return b
+	}
+	v, err := builderValue(value)
+	if err != nil {
+		b.err = fmt.Errorf("yaml: cannot set %s: %v", path, err)
+		return b
+	}
+	segments := splitPath(path)
+	if len(segments) == 0 {
+		b.err = fmt.Errorf("yaml: cannot set an empty path")
+		return b
+	}
+	var root interface{} = b.root
+	root, err = setPath(root, segments, v)
+	if err != nil {
+		b.err = fmt.Errorf("yaml: cannot set %s: %v", path, err)
+		return b
+	}
+	b.root = root.(yaml.MapSlice)
+	return b
+}
+
+// SetComment sets the comment written on the lines before the entry at
+// path, or at the top of the document for the empty path. The comment may
+// have several lines; each is written after "# ". Comments of entries that
+// are not set are dropped, as are those of entries within flow collections.
+func (b *MapBuilder) SetComment(path, comment string) *MapBuilder {
+	b.comments[path] = comment
+	return b
+}
+
+// Marshal returns the document built, written as JSONToYAMLWithOptions
+// would write it with opts, with the comments set.
+func (b *MapBuilder) Marshal(opts ...Option) ([]byte, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	root := b.root
+	if root == nil {
+		root = yaml.MapSlice{}
+	}
+	y, err := jsonObjectToYAML(root, newOptions(opts...))
+	if err != nil {
+		return nil, err
+	}
+	return addComments(y, b.comments)
+}
+
+// builderValue converts v to the form JSONToYAML gives values.
+func builderValue(v interface{}) (interface{}, error) {
+	if ms, ok := v.(yaml.MapSlice); ok {
+		return ms, nil
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var obj interface{}
+	if err := yaml.Unmarshal(j, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// setPath sets the value at the path segments within node, returning the
+// node updated. A nil node is created as a mapping or a sequence.
+func setPath(node interface{}, segments []string, v interface{}) (interface{}, error) {
+	if len(segments) == 0 {
+		return v, nil
+	}
+	segment := segments[0]
+	if strings.HasPrefix(segment, "[") {
+		s, ok := node.([]interface{})
+		if !ok && node != nil {
+			return nil, fmt.Errorf("%s applied to a non-sequence", segment)
+		}
+		i, err := strconv.Atoi(strings.TrimSuffix(segment[1:], "]"))
+		if err != nil || i < 0 || i > len(s) {
+			return nil, fmt.Errorf("index %s out of range", segment)
+		}
+		if i == len(s) {
+			s = append(s, nil)
+		}
+		if s[i], err = setPath(s[i], segments[1:], v); err != nil {
+			return nil, err
+		}
+		return s, nil
+	}
+
+	ms, ok := node.(yaml.MapSlice)
+	if !ok && node != nil {
+		return nil, fmt.Errorf("key %q set in a non-mapping", segment)
+	}
+	for i := range ms {
+		if k, ok := keyToString(ms[i].Key); ok && k == segment {
+			var err error
+			if ms[i].Value, err = setPath(ms[i].Value, segments[1:], v); err != nil {
+				return nil, err
+			}
+			return ms, nil
+		}
+	}
+	child, err := setPath(nil, segments[1:], v)
+	if err != nil {
+		return nil, err
+	}
+	if ms == nil {
+		ms = yaml.MapSlice{}
+	}
+	return append(ms, yaml.MapItem{Key: segment, Value: child}), nil
+}
+
+// addComments inserts comments, keyed by the path of the entry they
+// precede, into the YAML document y.
+func addComments(y []byte, comments map[string]string) ([]byte, error) {
+	if len(comments) == 0 {
+		return y, nil
+	}
+	nodes, err := blockNodes(y, scanTokens(y))
+	if err != nil {
+		return nil, err
+	}
+	var out bytes.Buffer
+	if c, ok := comments[""]; ok {
+		writeComment(&out, c, "")
+	}
+	last := 0
+	done := map[string]bool{}
+	for _, n := range nodes {
+		c, ok := comments[n.path]
+		if !ok || done[n.path] {
+			continue
+		}
+		done[n.path] = true
+		bol := n.start - (n.column - 1)
+		out.Write(y[last:bol])
+		writeComment(&out, c, strings.Repeat(" ", n.column-1))
+		last = bol
+	}
+	out.Write(y[last:])
+	return out.Bytes(), nil
+}
+
+// writeComment writes each line of comment to buf as a comment line
+// indented by indent.
+func writeComment(buf *bytes.Buffer, comment, indent string) {
+	for _, line := range strings.Split(comment, "\n") {
+		buf.WriteString(indent)
+		buf.WriteString(strings.TrimRight("# "+line, " "))
+		buf.WriteByte('\n')
+	}
+}
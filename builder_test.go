@@ -0,0 +1,87 @@
+package yaml
+
+import (
+	"testing"
+
+	"gopkg.in/yaml.v2"
+)
+
+func TestMapBuilder(t *testing.T) {
+	type port struct {
+		Name string `json:"name"`
+		Port int    `json:"port"`
+	}
+	got, err := NewMapBuilder().
+		Set("kind", "Service").
+		Set("apiVersion", "v1").
+		Set("metadata.name", "web").
+		Set("metadata.labels", map[string]string{"tier": "front", "app": "web"}).
+		Set("spec.ports[0]", port{Name: "http", Port: 80}).
+		Set("spec.ports[1].name", "https").
+		Set("spec.ports[1].port", 443).
+		Set("spec.selector", yaml.MapSlice{{Key: "z", Value: 1}, {Key: "a", Value: 2}}).
+		Set("kind", "Service").
+		SetComment("", "Generated; do not edit.").
+		SetComment("metadata.name", "Referenced by the ingress.\nKeep short.").
+		SetComment("spec.ports[1]", "TLS").
+		SetComment("spec.ports[1].port", "standard").
+		SetComment("spec.missing", "dropped").
+		Marshal()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# Generated; do not edit.
+kind: Service
+apiVersion: v1
+metadata:
+  # Referenced by the ingress.
+  # Keep short.
+  name: web
+  labels:
+    app: web
+    tier: front
+spec:
+  ports:
+  - name: http
+    port: 80
+  # TLS
+  - name: https
+    # standard
+    port: 443
+  selector:
+    z: 1
+    a: 2
+`
+	if string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+	var v interface{}
+	if err := Unmarshal(got, &v); err != nil {
+		t.Errorf("output does not decode: %v", err)
+	}
+
+	got, err = NewMapBuilder().Set("a.b", "x").Marshal(EmitNullAsEmpty())
+	if err != nil || string(got) != "a:\n  b: x\n" {
+		t.Errorf("got %q, %v", got, err)
+	}
+	if got, err := NewMapBuilder().Marshal(); err != nil || string(got) != "{}\n" {
+		t.Errorf("empty builder gave %q, %v", got, err)
+	}
+
+	errTests := []struct {
+		name string
+		b    *MapBuilder
+		want string
+	}{
+		{"key in scalar", NewMapBuilder().Set("a", 1).Set("a.b", 2).Set("c", 3), `yaml: cannot set a.b: key "b" set in a non-mapping`},
+		{"index out of range", NewMapBuilder().Set("a[1]", 1), "yaml: cannot set a[1]: index [1] out of range"},
+		{"index in mapping", NewMapBuilder().Set("a.b", 1).Set("a[0]", 1), "yaml: cannot set a[0]: [0] applied to a non-sequence"},
+		{"unencodable value", NewMapBuilder().Set("a", func() {}), "yaml: cannot set a: json: unsupported type: func()"},
+		{"empty path", NewMapBuilder().Set("", 1), "yaml: cannot set an empty path"},
+	}
+	for _, tt := range errTests {
+		if _, err := tt.b.Marshal(); err == nil || err.Error() != tt.want {
+			t.Errorf("%s: got error %v, want %q", tt.name, err, tt.want)
+		}
+	}
+}
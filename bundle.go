@@ -0,0 +1,136 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ComposeBundle combines the YAML streams in sources, typically the
+// contents of several manifest files, into a single multi-document stream.
+//
+// Documents are copied as written, comments included, with blank lines
+// trimmed from their ends. Null documents are dropped, and so are empty ones
+// unless EmptyDocuments says otherwise. Consecutive documents are separated
+// by a single "---" line. Documents keep their input order unless reordered
+// with OrderByKind.
+//
+// An error is returned if a document is not valid YAML, or if two documents
+// describe the same resource, i.e. share apiVersion, kind, namespace and
+// name. Documents without a kind and name are not checked for duplicates.
+func ComposeBundle(sources [][]byte, opts ...Option) ([]byte, error) {
+	o := newOptions(opts...)
+
+	type document struct {
+		text []byte
+		kind string
+	}
+	var docs []document
+	seen := map[resourceIdentity]int{}
+	for _, src := range sources {
+		for _, d := range splitDocuments(src) {
+			text := trimBlankLines(src[d.start:d.end])
+			if d.empty {
+				switch o.emptyDocuments {
+				case NullEmptyDocuments:
+					docs = append(docs, document{text: []byte("null")})
+					continue
+				case RejectEmptyDocuments:
+					return nil, emptyDocumentError(src, d)
+				}
+			}
+			var obj interface{}
+			if err := yaml.Unmarshal(text, &obj); err != nil {
+				return nil, fmt.Errorf("document %d: %v", len(docs)+1, err)
+			}
+			if obj == nil {
+				continue
+			}
+			id := identify(obj)
+			if id.Kind != "" && id.Name != "" {
+				if i, ok := seen[id]; ok {
+					return nil, fmt.Errorf("documents %d and %d both define %s", i, len(docs)+1, id)
+				}
+				seen[id] = len(docs) + 1
+			}
+			docs = append(docs, document{text: text, kind: id.Kind})
+		}
+	}
+
+	if len(o.kindOrder) > 0 {
+		rank := func(kind string) int {
+			for i, k := range o.kindOrder {
+				if k == kind {
+					return i
+				}
+			}
+			return len(o.kindOrder)
+		}
+		sort.SliceStable(docs, func(i, j int) bool {
+			return rank(docs[i].kind) < rank(docs[j].kind)
+		})
+	}
+
+	var out bytes.Buffer
+	for i, d := range docs {
+		if i > 0 {
+			if d.text[0] == '%' {
+				// Directives must follow an explicit document end.
+				out.WriteString("...\n")
+			} else {
+				out.WriteString("---\n")
+			}
+		}
+		out.Write(d.text)
+		out.WriteByte('\n')
+	}
+	return out.Bytes(), nil
+}
+
+// resourceIdentity identifies a Kubernetes-style resource.
+type resourceIdentity struct {
+	APIVersion string
+	Kind       string
+	Namespace  string
+	Name       string
+}
+
+func (id resourceIdentity) String() string {
+	name := id.Name
+	if id.Namespace != "" {
+		name = id.Namespace + "/" + name
+	}
+	return fmt.Sprintf("%s %s %s", id.APIVersion, id.Kind, name)
+}
+
+// identify returns the identity of the resource described by obj, as
+// decoded by go-yaml. Fields that are missing are left empty.
+func identify(obj interface{}) resourceIdentity {
+	var id resourceIdentity
+	m, ok := obj.(map[interface{}]interface{})
+	if !ok {
+		return id
+	}
+	id.APIVersion, _ = m["apiVersion"].(string)
+	id.Kind, _ = m["kind"].(string)
+	if meta, ok := m["metadata"].(map[interface{}]interface{}); ok {
+		id.Namespace, _ = meta["namespace"].(string)
+		id.Name, _ = meta["name"].(string)
+	}
+	return id
+}
+
+// trimBlankLines removes leading and trailing blank lines from y, as well as
+// the final line break.
+func trimBlankLines(y []byte) []byte {
+	for len(y) > 0 {
+		end := lineEnd(y, 0)
+		if len(bytes.TrimSpace(y[:end])) > 0 || end == len(y) {
+			break
+		}
+		y = y[end+1:]
+	}
+	return bytes.TrimRight(y, " \t\r\n")
+}
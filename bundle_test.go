@@ -0,0 +1,96 @@
+package yaml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestComposeBundle(t *testing.T) {
+	deploy := []byte(`# The app.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+`)
+	ns := []byte(`---
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+...
+`)
+
+	got, err := ComposeBundle([][]byte{deploy, ns})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `# The app.
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: prod
+---
+apiVersion: v1
+kind: Service
+metadata:
+  name: web
+  namespace: prod
+---
+apiVersion: v1
+kind: Namespace
+metadata:
+  name: prod
+`
+	if string(got) != want {
+		t.Errorf("ComposeBundle() = %q, want %q", got, want)
+	}
+
+	got, err = ComposeBundle([][]byte{deploy, ns}, OrderByKind(DefaultKindOrder...))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	kinds := []string{"Namespace", "Service", "Deployment"}
+	docs, _ := SplitDocuments(got)
+	for i, d := range docs {
+		if !strings.Contains(string(d), "kind: "+kinds[i]+"\n") {
+			t.Errorf("document %d = %q, want kind %s", i, d, kinds[i])
+		}
+	}
+
+	if _, err := ComposeBundle([][]byte{deploy, deploy}); err == nil {
+		t.Error("expected error for duplicate resources")
+	} else if !strings.Contains(err.Error(), "documents 1 and 3 both define apps/v1 Deployment prod/web") {
+		t.Errorf("unexpected error: %v", err)
+	}
+
+	if _, err := ComposeBundle([][]byte{[]byte("a: [")}); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestComposeBundleEmptyDocuments(t *testing.T) {
+	sources := [][]byte{[]byte("a: 1\n---\n# nothing\n---\nnull\n"), []byte("b: 2\n")}
+
+	got, err := ComposeBundle(sources, EmptyDocuments(NullEmptyDocuments))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "a: 1\n---\nnull\n---\nb: 2\n"; string(got) != want {
+		t.Errorf("ComposeBundle() = %q, want %q", got, want)
+	}
+
+	_, err = ComposeBundle(sources, EmptyDocuments(RejectEmptyDocuments))
+	if err == nil || err.Error() != "yaml: line 2: empty document" {
+		t.Errorf("expected empty document error, got %v", err)
+	}
+}
@@ -0,0 +1,200 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// FieldCipher encrypts and decrypts the values selected by EncryptFields,
+// typically by calling out to a local key or a KMS.
+type FieldCipher interface {
+	// Encrypt returns the ciphertext to store in place of the value at
+	// path, given the JSON encoding of that value.
+	Encrypt(path string, plaintext []byte) (string, error)
+	// Decrypt reverses Encrypt, returning the JSON encoding of the value.
+	Decrypt(path string, ciphertext string) ([]byte, error)
+}
+
+// EncryptFields makes MarshalWithOptions replace selected values with their
+// ciphertext, and UnmarshalWithOptions replace them back with the decrypted
+// value. A value is selected if it belongs to a struct field with a
+// yamlencrypt tag, such as `json:"password" yamlencrypt:""`, or if its path
+// matches one of paths.
+//
+// Paths join mapping keys with "." and append "[i]" for sequence entries,
+// as in "data.password" or "users[0].token". In a pattern, "*" matches any
+// mapping key and "[*]" any sequence index.
+//
+// Whole mappings and sequences can be selected; they are encrypted as a
+// single value. Null values are left alone. When decoding, a selected value
+// that is not a string is an error.
+func EncryptFields(c FieldCipher, paths ...string) Option {
+	return func(o *options) {
+		o.cipher = c
+		o.encryptPaths = paths
+	}
+}
+
+// encryptJSON encrypts the values selected by o in the JSON document j, which
+// is the encoding of a value of type t.
+func encryptJSON(j []byte, t reflect.Type, o *options) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.UseNumber()
+	var obj interface{}
+	if err := d.Decode(&obj); err != nil {
+		return nil, err
+	}
+	obj, err := transformFields(obj, t, "", false, o, func(path string, v interface{}) (interface{}, error) {
+		plaintext, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return o.cipher.Encrypt(path, plaintext)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// decryptObject decrypts the values selected by o in obj, a JSON-compatible
+// object about to be decoded into a value of type t.
+func decryptObject(obj interface{}, t reflect.Type, o *options) (interface{}, error) {
+	return transformFields(obj, t, "", false, o, func(path string, v interface{}) (interface{}, error) {
+		ciphertext, ok := v.(string)
+		if !ok {
+			return nil, fmt.Errorf("expected an encrypted string, got %T", v)
+		}
+		plaintext, err := o.cipher.Decrypt(path, ciphertext)
+		if err != nil {
+			return nil, err
+		}
+		d := json.NewDecoder(bytes.NewReader(plaintext))
+		d.UseNumber()
+		var dv interface{}
+		if err := d.Decode(&dv); err != nil {
+			return nil, fmt.Errorf("invalid decrypted value: %v", err)
+		}
+		return dv, nil
+	})
+}
+
+// transformFields walks the JSON-compatible object obj alongside the Go type
+// t it encodes and replaces every selected value by the result of fn. tagged
+// reports whether obj belongs to a struct field marked for encryption.
+func transformFields(obj interface{}, t reflect.Type, path string, tagged bool, o *options,
+	fn func(path string, v interface{}) (interface{}, error)) (interface{}, error) {
+	if obj == nil {
+		return nil, nil
+	}
+	if tagged || matchAnyPath(o.encryptPaths, path) {
+		v, err := fn(path, obj)
+		if err != nil {
+			return nil, fmt.Errorf("error transforming %s: %v", path, err)
+		}
+		return v, nil
+	}
+
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var err error
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		for k, v := range typedObj {
+			var et reflect.Type
+			var encrypt bool
+			if t != nil {
+				switch t.Kind() {
+				case reflect.Struct:
+					if f := cachedStructFields(t).lookup([]byte(k)); f != nil {
+						et, encrypt = f.typ, f.encrypt
+					}
+				case reflect.Map:
+					et = t.Elem()
+				}
+			}
+			typedObj[k], err = transformFields(v, et, joinPath(path, k), encrypt, o, fn)
+			if err != nil {
+				return nil, err
+			}
+		}
+	case []interface{}:
+		var et reflect.Type
+		if t != nil && (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			et = t.Elem()
+		}
+		for i, v := range typedObj {
+			typedObj[i], err = transformFields(v, et, path+"["+strconv.Itoa(i)+"]", false, o, fn)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return obj, nil
+}
+
+// matchAnyPath reports whether path matches one of patterns.
+func matchAnyPath(patterns []string, path string) bool {
+	if path == "" {
+		return false
+	}
+	for _, p := range patterns {
+		if matchPath(p, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchPath reports whether path matches pattern, where "*" stands for any
+// mapping key and "[*]" for any sequence index.
+func matchPath(pattern, path string) bool {
+	ps, ss := splitPath(pattern), splitPath(path)
+	if len(ps) != len(ss) {
+		return false
+	}
+	for i := range ps {
+		switch {
+		case ps[i] == ss[i]:
+		case ps[i] == "*" && !strings.HasPrefix(ss[i], "["):
+		case ps[i] == "[*]" && strings.HasPrefix(ss[i], "["):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// splitPath splits a path into its mapping keys and "[i]" sequence indexes.
+func splitPath(path string) []string {
+	var segments []string
+	start := 0
+	for i := 0; i < len(path); i++ {
+		switch path[i] {
+		case '.':
+			segments = append(segments, path[start:i])
+			start = i + 1
+		case '[':
+			if i > start {
+				segments = append(segments, path[start:i])
+			}
+			start = i
+		case ']':
+			segments = append(segments, path[start:i+1])
+			start = i + 1
+			if start < len(path) && path[start] == '.' {
+				i++
+				start++
+			}
+		}
+	}
+	if start < len(path) {
+		segments = append(segments, path[start:])
+	}
+	return segments
+}
@@ -0,0 +1,98 @@
+package yaml
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// base64Cipher stands in for a real cipher, wrapping values in "ENC[...]".
+type base64Cipher struct{}
+
+func (base64Cipher) Encrypt(path string, plaintext []byte) (string, error) {
+	return "ENC[" + base64.StdEncoding.EncodeToString(plaintext) + "]", nil
+}
+
+func (base64Cipher) Decrypt(path string, ciphertext string) ([]byte, error) {
+	if !strings.HasPrefix(ciphertext, "ENC[") || !strings.HasSuffix(ciphertext, "]") {
+		return nil, errors.New("not encrypted")
+	}
+	return base64.StdEncoding.DecodeString(ciphertext[4 : len(ciphertext)-1])
+}
+
+type CipherCredentials struct {
+	User     string `json:"user"`
+	Password string `json:"password" yamlencrypt:""`
+	PIN      int    `json:"pin" yamlencrypt:""`
+}
+
+type CipherConfig struct {
+	Name  string              `json:"name"`
+	Creds []CipherCredentials `json:"creds"`
+	Data  map[string]string   `json:"data"`
+	Extra interface{}         `json:"extra,omitempty"`
+}
+
+func TestEncryptFields(t *testing.T) {
+	in := CipherConfig{
+		Name:  "app",
+		Creds: []CipherCredentials{{User: "ann", Password: "hunter2", PIN: 1234}},
+		Data:  map[string]string{"token": "abc", "plain": "xyz"},
+		Extra: map[string]interface{}{"a": []interface{}{"b"}},
+	}
+	opt := EncryptFields(base64Cipher{}, "data.token", "extra")
+
+	y, err := MarshalWithOptions(in, opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `creds:
+- password: ENC[Imh1bnRlcjIi]
+  pin: ENC[MTIzNA==]
+  user: ann
+data:
+  plain: xyz
+  token: ENC[ImFiYyI=]
+extra: ENC[eyJhIjpbImIiXX0=]
+name: app
+`
+	if string(y) != want {
+		t.Errorf("MarshalWithOptions() = %q, want %q", y, want)
+	}
+
+	var out CipherConfig
+	if err := UnmarshalWithOptions(y, &out, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Errorf("UnmarshalWithOptions() = %#v, want %#v", out, in)
+	}
+
+	err = UnmarshalWithOptions([]byte("creds:\n- password: hunter2\n"), &out, opt)
+	if err == nil || !strings.Contains(err.Error(), "creds[0].password") {
+		t.Errorf("expected error for unencrypted value, got %v", err)
+	}
+}
+
+func TestMatchPath(t *testing.T) {
+	tests := []struct {
+		pattern, path string
+		want          bool
+	}{
+		{"a.b", "a.b", true},
+		{"a.b", "a.b.c", false},
+		{"a.*", "a.b", true},
+		{"a.*", "a[0]", false},
+		{"a[*].b", "a[3].b", true},
+		{"a[*].b", "a.x.b", false},
+		{"a[0][*]", "a[0][1]", true},
+		{"*.c", "a[0].c", false},
+	}
+	for _, tt := range tests {
+		if got := matchPath(tt.pattern, tt.path); got != tt.want {
+			t.Errorf("matchPath(%q, %q) = %v, want %v", tt.pattern, tt.path, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package yaml
+
+import "errors"
+
+// Codec converts between Go values, YAML and JSON with a fixed set of
+// options. A Codec is immutable once created and safe for concurrent use, so
+// a single configured Codec can be shared by all the goroutines of a
+// program, such as the handlers of a server.
+//
+// Each call processes the options afresh, so state that options keep during
+// a call, such as the count reported to WithMetrics, is never shared between
+// calls. Functions given as options, such as a WithMetrics callback or a
+// Logger, are called from the goroutine making the call and must themselves
+// be safe for concurrent use if the Codec is shared.
+//
+// RecordRanges writes to the map it is given and so cannot be shared: the
+// Unmarshal method of a Codec created with it always fails.
+type Codec struct {
+	opts []Option
+	err  error
+}
+
+// NewCodec returns a Codec that honors the given options.
+func NewCodec(opts ...Option) *Codec {
+	// Copy the options so that the caller cannot change them later.
+	c := &Codec{opts: append([]Option(nil), opts...)}
+	if newOptions(opts...).ranges != nil {
+		c.err = errors.New("yaml: RecordRanges cannot be used with a Codec")
+	}
+	return c
+}
+
+// Marshal is like MarshalWithOptions with the options of c.
+func (c *Codec) Marshal(o interface{}) ([]byte, error) {
+	return MarshalWithOptions(o, c.opts...)
+}
+
+// Unmarshal is like UnmarshalWithOptions with the options of c.
+func (c *Codec) Unmarshal(y []byte, o interface{}) error {
+	if c.err != nil {
+		return c.err
+	}
+	return UnmarshalWithOptions(y, o, c.opts...)
+}
+
+// JSONToYAML is like JSONToYAMLWithOptions with the options of c.
+func (c *Codec) JSONToYAML(j []byte) ([]byte, error) {
+	return JSONToYAMLWithOptions(j, c.opts...)
+}
+
+// YAMLToJSON is like YAMLToJSONWithOptions with the options of c.
+func (c *Codec) YAMLToJSON(y []byte) ([]byte, error) {
+	return YAMLToJSONWithOptions(y, c.opts...)
+}
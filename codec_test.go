@@ -0,0 +1,91 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// TestCodecConcurrent shares a Codec between goroutines; run it with -race
+// to check that calls do not share state.
+func TestCodecConcurrent(t *testing.T) {
+	type config struct {
+		Name     string `json:"name"`
+		Replicas int    `json:"replicas"`
+	}
+	var strictErrors int64
+	c := NewCodec(Strict(), MapFieldNames(SnakeCase), WithMetrics(func(m Metrics) {
+		atomic.AddInt64(&strictErrors, int64(m.StrictErrors))
+	}))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			want := config{Name: fmt.Sprintf("app%d", i), Replicas: i}
+			y, err := c.Marshal(want)
+			if err != nil {
+				errs <- err
+				return
+			}
+			var got config
+			if err := c.Unmarshal(y, &got); err != nil {
+				errs <- err
+				return
+			}
+			if !reflect.DeepEqual(got, want) {
+				errs <- fmt.Errorf("round trip of %+v gave %+v", want, got)
+				return
+			}
+			if i%2 == 0 {
+				// One unknown field per even goroutine.
+				if err := c.Unmarshal([]byte("name: x\nextra: 1\n"), &got); err == nil {
+					errs <- fmt.Errorf("expected an error for an unknown field")
+				}
+			}
+			j, err := c.YAMLToJSON(y)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if _, err := c.JSONToYAML(j); err != nil {
+				errs <- err
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if strictErrors != 10 {
+		t.Errorf("got %d strict errors, want 10", strictErrors)
+	}
+}
+
+func TestNewCodecCopiesOptions(t *testing.T) {
+	opts := []Option{JSONArrayAsDocuments()}
+	c := NewCodec(opts...)
+	opts[0] = DisableLineWrap()
+
+	got, err := c.JSONToYAML([]byte(`[1,2]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "1\n--- 2\n"; string(got) != want {
+		t.Errorf("JSONToYAML() = %q, want %q", got, want)
+	}
+}
+
+func TestCodecRejectsRecordRanges(t *testing.T) {
+	c := NewCodec(RecordRanges(map[string]Range{}))
+	var v map[string]int
+	err := c.Unmarshal([]byte("a: 1\n"), &v)
+	if want := "yaml: RecordRanges cannot be used with a Codec"; err == nil || err.Error() != want {
+		t.Errorf("Unmarshal() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,317 @@
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// StripComments removes all comments from the YAML stream doc without
+// otherwise reformatting it. Lines holding nothing but a comment are removed
+// entirely; trailing comments are removed along with the whitespace before
+// them. Comment-like text inside scalars is left alone.
+//
+// KeepHeaderComments and KeepShebang retain the comments at the top of the
+// stream. Other options are ignored.
+//
+// An error is returned if doc is not valid YAML, or if stripping would
+// change its content.
+func StripComments(doc []byte, opts ...Option) ([]byte, error) {
+	o := newOptions(opts...)
+
+	want, err := yamlUnmarshalAll(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	last := 0
+	header := 1
+	for i, t := range scanTokens(doc) {
+		if t.kind != commentToken {
+			continue
+		}
+		if t.ownLine && t.line == header {
+			header++
+			if o.keepHeaderComments || (i == 0 && t.start == 0 && o.keepShebang &&
+				bytes.HasPrefix(doc, []byte("#!"))) {
+				continue
+			}
+		}
+
+		start, end := t.start, t.end
+		if t.ownLine {
+			// Remove the whole line, including its line break.
+			start -= t.column - 1
+			for end < len(doc) && doc[end] != '\n' {
+				end++
+			}
+			if end < len(doc) {
+				end++
+			}
+		} else {
+			for start > 0 && isBlank(doc[start-1]) {
+				start--
+			}
+		}
+		out.Write(doc[last:start])
+		last = end
+	}
+	out.Write(doc[last:])
+
+	got, err := yamlUnmarshalAll(out.Bytes())
+	if err != nil || !reflect.DeepEqual(got, want) {
+		return nil, errors.New("unable to strip comments without changing the document")
+	}
+	return out.Bytes(), nil
+}
+
+// yamlUnmarshalAll unmarshals every document of the YAML stream y.
+func yamlUnmarshalAll(y []byte) ([]interface{}, error) {
+	var docs []interface{}
+	d := yaml.NewDecoder(bytes.NewReader(y))
+	for {
+		var doc interface{}
+		err := d.Decode(&doc)
+		if err == io.EOF {
+			return docs, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+}
+
+// Comments holds the comments associated with a node. Each field holds the
+// comment lines as written, including their '#', joined by newlines.
+type Comments struct {
+	// Head holds the comment lines directly above the node.
+	Head string
+	// Line holds the comment at the end of the node's line.
+	Line string
+	// Foot holds the comment lines below the node that are not directly
+	// followed by another node, such as a comment closing a section.
+	Foot string
+}
+
+// ExtractComments returns the comments of the first document in doc, keyed
+// by the path of the node they belong to. Paths join mapping keys with "."
+// and append "[i]" for sequence entries, e.g. "spec.containers[0].image";
+// comments belonging to the document as a whole use the empty path.
+//
+// Nodes are located from the block structure of the document. Comments
+// inside flow collections and multi-line scalars are attributed to the
+// block node that contains them.
+func ExtractComments(doc []byte) (map[string]Comments, error) {
+	var obj interface{}
+	if err := yaml.Unmarshal(doc, &obj); err != nil {
+		return nil, err
+	}
+
+	tokens := scanTokens(doc)
+	nodes, err := blockNodes(doc, tokens)
+	if err != nil {
+		return nil, err
+	}
+	// byLine maps a line to the innermost node starting on it.
+	byLine := map[int]blockNode{}
+	for _, n := range nodes {
+		byLine[n.line] = n
+	}
+	// preceding returns the innermost node starting at or before line that
+	// is not indented past column, or the document itself. A sequence entry
+	// at column belongs to a sequence nested in the node at column.
+	preceding := func(line, column int) string {
+		for i := len(nodes) - 1; i >= 0; i-- {
+			n := nodes[i]
+			if n.line <= line && (n.column < column || (n.column == column && !n.entry)) {
+				return n.path
+			}
+		}
+		return ""
+	}
+
+	comments := map[string]Comments{}
+	add := func(path string, field func(*Comments) *string, text string) {
+		c := comments[path]
+		f := field(&c)
+		if *f != "" {
+			*f += "\n"
+		}
+		*f += text
+		comments[path] = c
+	}
+	head := func(c *Comments) *string { return &c.Head }
+	line := func(c *Comments) *string { return &c.Line }
+	foot := func(c *Comments) *string { return &c.Foot }
+
+	docs := 0
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if t.kind == documentToken {
+			if docs++; docs > 1 || len(nodes) > 0 && t.start > nodes[0].start {
+				break
+			}
+		}
+		if t.kind != commentToken {
+			continue
+		}
+		if !t.ownLine {
+			n, ok := byLine[t.line]
+			path := n.path
+			if !ok {
+				path = preceding(t.line, len(doc))
+			}
+			add(path, line, string(doc[t.start:t.end]))
+			continue
+		}
+
+		// Gather the block of comment lines starting here.
+		block := []token{t}
+		for i+1 < len(tokens) && tokens[i+1].kind == commentToken && tokens[i+1].ownLine &&
+			tokens[i+1].line == block[len(block)-1].line+1 {
+			i++
+			block = append(block, tokens[i])
+		}
+		var text []string
+		for _, c := range block {
+			text = append(text, string(doc[c.start:c.end]))
+		}
+		if n, ok := byLine[block[len(block)-1].line+1]; ok {
+			add(n.path, head, strings.Join(text, "\n"))
+		} else if len(nodes) == 0 || t.start < nodes[0].start {
+			add("", head, strings.Join(text, "\n"))
+		} else {
+			add(preceding(t.line, t.column), foot, strings.Join(text, "\n"))
+		}
+	}
+	return comments, nil
+}
+
+// blockNode is a block mapping entry or block sequence entry.
+type blockNode struct {
+	path string
+	// key is the decoded key of a mapping entry, or "[i]" for the i-th
+	// sequence entry, and parent the index of the node holding its
+	// collection, or -1 at the document's root.
+	key    string
+	parent int
+	entry  bool
+	start  int
+	line   int
+	column int
+}
+
+// keyLines returns the line of each path of the block collection entries
+// of the first document in y, or an empty map if y cannot be scanned.
+func keyLines(y []byte) map[string]int {
+	lines := map[string]int{}
+	nodes, err := blockNodes(y, scanTokens(y))
+	if err != nil {
+		return lines
+	}
+	for _, n := range nodes {
+		if _, ok := lines[n.path]; !ok {
+			lines[n.path] = n.line
+		}
+	}
+	return lines
+}
+
+// blockNodes returns the block collection entries of the first document
+// found among tokens, with their paths.
+func blockNodes(doc []byte, tokens []token) ([]blockNode, error) {
+	type frame struct {
+		column int
+		seq    bool
+		path   string
+		node   int
+		index  int
+	}
+	var frames []frame
+	var nodes []blockNode
+	docs := 0
+	for _, t := range tokens {
+		switch t.kind {
+		case documentToken:
+			if docs++; docs > 1 || len(nodes) > 0 {
+				return nodes, nil
+			}
+			continue
+		case keyToken, entryToken:
+		default:
+			continue
+		}
+
+		// Close the collections this entry is not part of. A sequence at the
+		// same column as a mapping key is the value of the previous key.
+		for len(frames) > 0 {
+			f := frames[len(frames)-1]
+			if f.column > t.column || (t.kind == keyToken && f.seq && f.column == t.column) {
+				frames = frames[:len(frames)-1]
+				continue
+			}
+			break
+		}
+		// The path and index of the node owning the collection.
+		parent, parentNode := "", -1
+		if len(frames) > 0 {
+			parent, parentNode = frames[len(frames)-1].path, frames[len(frames)-1].node
+		}
+
+		var path, key string
+		if t.kind == keyToken {
+			key = string(doc[t.start:t.end])
+			if key[0] == '\'' || key[0] == '"' {
+				if err := yaml.Unmarshal(doc[t.start:t.end], &key); err != nil {
+					return nil, err
+				}
+			}
+			if n := len(frames); n > 0 && !frames[n-1].seq && frames[n-1].column == t.column {
+				// Another key of the same mapping.
+				frames = frames[:n-1]
+				parent, parentNode = "", -1
+				if n > 1 {
+					parent, parentNode = frames[n-2].path, frames[n-2].node
+				}
+			}
+			path = key
+			if parent != "" {
+				path = parent + "." + key
+			}
+			frames = append(frames, frame{column: t.column, path: path, node: len(nodes)})
+		} else {
+			n := len(frames)
+			if n > 0 && frames[n-1].seq && frames[n-1].column == t.column {
+				frames[n-1].index++
+				parent, parentNode = "", -1
+				if n > 1 {
+					parent, parentNode = frames[n-2].path, frames[n-2].node
+				}
+			} else {
+				frames = append(frames, frame{column: t.column, seq: true})
+				n++
+			}
+			key = fmt.Sprintf("[%d]", frames[n-1].index)
+			path = parent + key
+			frames[n-1].path = path
+			frames[n-1].node = len(nodes)
+		}
+		nodes = append(nodes, blockNode{
+			path:   path,
+			key:    key,
+			parent: parentNode,
+			entry:  t.kind == entryToken,
+			start:  t.start,
+			line:   t.line,
+			column: t.column,
+		})
+	}
+	return nodes, nil
+}
@@ -0,0 +1,133 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestStripComments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		want  string
+	}{
+		{
+			name:  "no comments",
+			input: "a: 1\nb:\n  - c\n",
+			want:  "a: 1\nb:\n  - c\n",
+		},
+		{
+			name:  "own line and trailing comments",
+			input: "# head\na: 1 # one\n  # indented\nb: [x, y]  # flow\n# foot\n",
+			want:  "a: 1\nb: [x, y]\n",
+		},
+		{
+			name:  "hash inside scalars",
+			input: "a: 'x # y'\nb: \"x # y\"\nc: x#y\nd: http://h/#frag\n",
+			want:  "a: 'x # y'\nb: \"x # y\"\nc: x#y\nd: http://h/#frag\n",
+		},
+		{
+			name:  "multi-line quoted scalar",
+			input: "a: \"first\n  # not a comment\n  last\" # comment\n",
+			want:  "a: \"first\n  # not a comment\n  last\"\n",
+		},
+		{
+			name:  "block scalars",
+			input: "a: | # header\n  # kept\n  text\n# removed\nb:\n  - >-\n    # kept\n\n    more\n  # removed\n",
+			want:  "a: |\n  # kept\n  text\nb:\n  - >-\n    # kept\n\n    more\n",
+		},
+		{
+			name:  "blank lines between sections",
+			input: "# server\nhost: h\nport: 80\n\n# storage\npath: /p\n\n\nsize: 1\n",
+			want:  "host: h\nport: 80\n\npath: /p\n\n\nsize: 1\n",
+		},
+		{
+			name:  "documents",
+			input: "--- # first\na: 1\n--- # second\nb: 2\n",
+			want:  "---\na: 1\n---\nb: 2\n",
+		},
+		{
+			name:  "header kept",
+			input: "# Copyright\n# License\n\n# about a\na: 1 # one\n",
+			opts:  []Option{KeepHeaderComments()},
+			want:  "# Copyright\n# License\n\na: 1\n",
+		},
+		{
+			name:  "shebang kept",
+			input: "#!/usr/bin/env tool\n# usage\na: 1\n",
+			opts:  []Option{KeepShebang()},
+			want:  "#!/usr/bin/env tool\na: 1\n",
+		},
+		{
+			name:  "shebang removed by default",
+			input: "#!/usr/bin/env tool\na: 1\n",
+			want:  "a: 1\n",
+		},
+		{
+			name:  "CRLF line endings",
+			input: "# head\r\na: 1 # one\r\nb: 2\r\n",
+			want:  "a: 1\r\nb: 2\r\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := StripComments([]byte(tt.input), tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("StripComments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := StripComments([]byte("a: [1\n")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestExtractComments(t *testing.T) {
+	y := []byte(`# Example configuration.
+
+# The name of the app.
+name: web # required
+spec:
+  # Containers to run.
+  containers:
+  - name: app # main
+    # Image reference.
+    image: "nginx # latest"
+    args: [a, b] # flow
+  - name: sidecar
+
+  # End of containers.
+
+  "quoted key": |
+    # part of the scalar
+    text
+# trailing
+---
+other: 1 # not in the first document
+`)
+	got, err := ExtractComments(y)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]Comments{
+		"":                         {Head: "# Example configuration."},
+		"spec":                     {Foot: "# trailing"},
+		"name":                     {Head: "# The name of the app.", Line: "# required"},
+		"spec.containers":          {Head: "# Containers to run.", Foot: "# End of containers."},
+		"spec.containers[0].name":  {Line: "# main"},
+		"spec.containers[0].image": {Head: "# Image reference."},
+		"spec.containers[0].args":  {Line: "# flow"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractComments() = %#v, want %#v", got, want)
+	}
+
+	if _, err := ExtractComments([]byte("a: [")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
@@ -0,0 +1,195 @@
+package yaml
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Config captures the settings of the options that can be stored in
+// configuration, such as strictness, limits and styles, so that they can be
+// set up once, validated, and given to any function taking options:
+//
+//	if err := cfg.Validate(); err != nil {
+//		return err
+//	}
+//	err := yaml.UnmarshalWithOptions(data, &v, cfg.Option())
+//
+// Its fields are tagged to be decoded from the application's own YAML or
+// JSON configuration. The zero value is the default behavior. Each field
+// applies the option of the same name; options that take functions, such as
+// WithLogger, are given separately.
+type Config struct {
+	// Strict applies Strict, and GroupStrictErrors GroupStrictErrors.
+	Strict            bool `json:"strict,omitempty"`
+	GroupStrictErrors bool `json:"groupStrictErrors,omitempty"`
+
+	// Safe applies SafeProfile. The limits below, if set, override its own.
+	Safe bool `json:"safe,omitempty"`
+
+	DisallowAliases           bool `json:"disallowAliases,omitempty"`
+	DisallowMergeKeys         bool `json:"disallowMergeKeys,omitempty"`
+	DisallowCustomTags        bool `json:"disallowCustomTags,omitempty"`
+	DisallowDuplicateAnchors  bool `json:"disallowDuplicateAnchors,omitempty"`
+	DisallowControlCharacters bool `json:"disallowControlCharacters,omitempty"`
+	DisallowTrailingContent   bool `json:"disallowTrailingContent,omitempty"`
+	DetectTruncation          bool `json:"detectTruncation,omitempty"`
+	SingleDocument            bool `json:"singleDocument,omitempty"`
+	TranscodeInput            bool `json:"transcodeInput,omitempty"`
+	ApplyDefaults             bool `json:"applyDefaults,omitempty"`
+
+	// MaxInputSize and MaxDepth apply the options of the same name, if
+	// positive.
+	MaxInputSize int `json:"maxInputSize,omitempty"`
+	MaxDepth     int `json:"maxDepth,omitempty"`
+
+	// MaxErrorWidth applies MaxErrorWidth, if positive.
+	MaxErrorWidth int `json:"maxErrorWidth,omitempty"`
+
+	// TabWidth, if positive, applies TolerateTabIndentation with that width.
+	TabWidth int `json:"tabWidth,omitempty"`
+
+	// VersionKeys, if not empty, applies KeepVersionStrings with these
+	// patterns.
+	VersionKeys []string `json:"versionKeys,omitempty"`
+
+	// EmptyDocuments applies EmptyDocuments: "skip", "null" or "reject".
+	EmptyDocuments string `json:"emptyDocuments,omitempty"`
+
+	// OrderedMaps applies OrderedMaps, and Numbers selects the type of the
+	// numbers decoded by UnmarshalAny: "float64", the default, "number" for
+	// UseNumber or "int64" for UseInt64.
+	OrderedMaps bool   `json:"orderedMaps,omitempty"`
+	Numbers     string `json:"numbers,omitempty"`
+
+	// FloatFormat applies FormatFloats: "json" or "decimal".
+	FloatFormat   string `json:"floatFormat,omitempty"`
+	IntegerDigits bool   `json:"integerDigits,omitempty"`
+
+	// LineWidth applies LineWidth, if positive.
+	LineWidth       int  `json:"lineWidth,omitempty"`
+	DisableLineWrap bool `json:"disableLineWrap,omitempty"`
+	EmitNullAsEmpty bool `json:"emitNullAsEmpty,omitempty"`
+
+	// IndentJSON applies IndentJSON; it may hold only spaces and tabs.
+	IndentJSON string `json:"indentJSON,omitempty"`
+
+	AsDocuments        bool `json:"asDocuments,omitempty"`
+	KeepHeaderComments bool `json:"keepHeaderComments,omitempty"`
+	KeepShebang        bool `json:"keepShebang,omitempty"`
+
+	// KindOrder, if not empty, applies OrderByKind with these kinds.
+	KindOrder []string `json:"kindOrder,omitempty"`
+}
+
+var (
+	emptyDocumentPolicies = map[string]EmptyDocumentPolicy{
+		"skip":   SkipEmptyDocuments,
+		"null":   NullEmptyDocuments,
+		"reject": RejectEmptyDocuments,
+	}
+	numberModes = map[string]numberMode{
+		"float64": numbersAsFloat64,
+		"number":  numbersAsJSONNumber,
+		"int64":   numbersAsInt64,
+	}
+	floatFormats = map[string]FloatFormat{
+		"json":    JSONFloatFormat,
+		"decimal": DecimalFloatFormat,
+	}
+)
+
+// Validate returns an error describing the first invalid setting of c.
+func (c Config) Validate() error {
+	for _, limit := range []struct {
+		name  string
+		value int
+	}{
+		{"maxInputSize", c.MaxInputSize},
+		{"maxDepth", c.MaxDepth},
+		{"maxErrorWidth", c.MaxErrorWidth},
+		{"tabWidth", c.TabWidth},
+		{"lineWidth", c.LineWidth},
+	} {
+		if limit.value < 0 {
+			return fmt.Errorf("yaml: invalid config: %s must not be negative, got %d", limit.name, limit.value)
+		}
+	}
+	if _, ok := emptyDocumentPolicies[c.EmptyDocuments]; c.EmptyDocuments != "" && !ok {
+		return configNameError("emptyDocuments", c.EmptyDocuments, "skip", "null", "reject")
+	}
+	if _, ok := numberModes[c.Numbers]; c.Numbers != "" && !ok {
+		return configNameError("numbers", c.Numbers, "float64", "number", "int64")
+	}
+	if _, ok := floatFormats[c.FloatFormat]; c.FloatFormat != "" && !ok {
+		return configNameError("floatFormat", c.FloatFormat, "json", "decimal")
+	}
+	for _, p := range c.VersionKeys {
+		if _, err := path.Match(p, ""); err != nil {
+			return fmt.Errorf("yaml: invalid config: versionKeys: invalid pattern %q", p)
+		}
+	}
+	if strings.Trim(c.IndentJSON, " \t") != "" {
+		return fmt.Errorf("yaml: invalid config: indentJSON must hold only spaces and tabs, got %q", c.IndentJSON)
+	}
+	return nil
+}
+
+// configNameError returns the error for the unknown value of the setting
+// name, listing the known values.
+func configNameError(name, value string, known ...string) error {
+	return fmt.Errorf("yaml: invalid config: unknown %s %q: must be one of %q", name, value, known)
+}
+
+// Option returns an Option applying the settings of c. Settings that
+// Validate would reject are ignored.
+func (c Config) Option() Option {
+	return func(o *options) {
+		var opts []Option
+		add := func(set bool, opt Option) {
+			if set {
+				opts = append(opts, opt)
+			}
+		}
+		add(c.Safe, SafeProfile())
+		add(c.Strict, Strict())
+		add(c.GroupStrictErrors, GroupStrictErrors())
+		add(c.DisallowAliases, DisallowAliases())
+		add(c.DisallowMergeKeys, DisallowMergeKeys())
+		add(c.DisallowCustomTags, DisallowCustomTags())
+		add(c.DisallowDuplicateAnchors, DisallowDuplicateAnchors())
+		add(c.DisallowControlCharacters, DisallowControlCharacters())
+		add(c.DisallowTrailingContent, DisallowTrailingContent())
+		add(c.DetectTruncation, DetectTruncation())
+		add(c.SingleDocument, SingleDocument())
+		add(c.TranscodeInput, TranscodeInput())
+		add(c.ApplyDefaults, ApplyDefaults())
+		add(c.MaxInputSize > 0, MaxInputSize(c.MaxInputSize))
+		add(c.MaxDepth > 0, MaxDepth(c.MaxDepth))
+		add(c.MaxErrorWidth > 0, MaxErrorWidth(c.MaxErrorWidth))
+		add(c.TabWidth > 0, TolerateTabIndentation(c.TabWidth, nil))
+		add(len(c.VersionKeys) > 0, KeepVersionStrings(c.VersionKeys...))
+		if p, ok := emptyDocumentPolicies[c.EmptyDocuments]; ok {
+			opts = append(opts, EmptyDocuments(p))
+		}
+		add(c.OrderedMaps, OrderedMaps())
+		if n, ok := numberModes[c.Numbers]; ok {
+			opts = append(opts, func(o *options) { o.numbers = n })
+		}
+		if f, ok := floatFormats[c.FloatFormat]; ok {
+			opts = append(opts, FormatFloats(f))
+		}
+		add(c.IntegerDigits, IntegerDigits())
+		add(c.LineWidth > 0, LineWidth(c.LineWidth))
+		add(c.DisableLineWrap, DisableLineWrap())
+		add(c.EmitNullAsEmpty, EmitNullAsEmpty())
+		add(c.IndentJSON != "", IndentJSON(c.IndentJSON))
+		add(c.AsDocuments, AsDocuments())
+		add(c.KeepHeaderComments, KeepHeaderComments())
+		add(c.KeepShebang, KeepShebang())
+		add(len(c.KindOrder) > 0, OrderByKind(c.KindOrder...))
+		for _, opt := range opts {
+			opt(o)
+		}
+	}
+}
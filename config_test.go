@@ -0,0 +1,89 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConfigOption(t *testing.T) {
+	var cfg Config
+	err := Unmarshal([]byte(`
+strict: true
+safe: true
+maxDepth: 20
+tabWidth: 4
+versionKeys: ["*Version"]
+emptyDocuments: reject
+numbers: int64
+floatFormat: decimal
+indentJSON: "  "
+kindOrder: [Namespace]
+`), &cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate() = %v", err)
+	}
+
+	got := newOptions(cfg.Option())
+	want := newOptions(
+		SafeProfile(),
+		Strict(),
+		MaxDepth(20),
+		TolerateTabIndentation(4, nil),
+		KeepVersionStrings("*Version"),
+		EmptyDocuments(RejectEmptyDocuments),
+		UseInt64(),
+		FormatFloats(DecimalFloatFormat),
+		IndentJSON("  "),
+		OrderByKind("Namespace"),
+	)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Option() applied %+v, want %+v", got, want)
+	}
+	if got.maxInputSize != SafeMaxInputSize {
+		t.Errorf("maxInputSize = %d, want the SafeProfile limit", got.maxInputSize)
+	}
+
+	if got := newOptions(Config{}.Option()); !reflect.DeepEqual(got, newOptions()) {
+		t.Errorf("zero Config applied %+v", got)
+	}
+}
+
+func TestConfigUse(t *testing.T) {
+	cfg := Config{Strict: true, IndentJSON: "\t"}
+	var v struct {
+		A int `json:"a"`
+	}
+	if err := UnmarshalWithOptions([]byte("a: 1\nb: 2\n"), &v, cfg.Option()); err == nil {
+		t.Error("UnmarshalWithOptions() accepted an unknown field")
+	}
+	j, err := YAMLToJSONWithOptions([]byte("a: 1\n"), cfg.Option())
+	if err != nil || string(j) != "{\n\t\"a\": 1\n}" {
+		t.Errorf("YAMLToJSONWithOptions() = %q, %v", j, err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	tests := []struct {
+		cfg     Config
+		wantErr string
+	}{
+		{Config{MaxDepth: -1}, "maxDepth must not be negative, got -1"},
+		{Config{TabWidth: -2}, "tabWidth must not be negative, got -2"},
+		{Config{LineWidth: -1}, "lineWidth must not be negative, got -1"},
+		{Config{EmptyDocuments: "drop"}, `unknown emptyDocuments "drop": must be one of ["skip" "null" "reject"]`},
+		{Config{Numbers: "int"}, `unknown numbers "int": must be one of ["float64" "number" "int64"]`},
+		{Config{FloatFormat: "exp"}, `unknown floatFormat "exp": must be one of ["json" "decimal"]`},
+		{Config{VersionKeys: []string{"[v"}}, `versionKeys: invalid pattern "[v"`},
+		{Config{IndentJSON: "--"}, `indentJSON must hold only spaces and tabs, got "--"`},
+	}
+	for _, tt := range tests {
+		err := tt.cfg.Validate()
+		if err == nil || !strings.HasPrefix(err.Error(), "yaml: invalid config: ") || !strings.HasSuffix(err.Error(), tt.wantErr) {
+			t.Errorf("Validate(%+v) = %v, want %q", tt.cfg, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package yaml
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DisallowControlCharacters makes the decoding functions that take options
+// reject documents with strings holding NUL or another ASCII control
+// character other than tab, line feed and carriage return. go-yaml already
+// refuses such characters written as is, but accepts them written as escapes
+// in double-quoted scalars, such as "\0" or "\x1b", which are common in
+// corrupted or malicious input and break consumers of the resulting JSON.
+// The error gives the position of the offending scalar.
+func DisallowControlCharacters() Option {
+	return func(o *options) {
+		o.disallowControlCharacters = true
+	}
+}
+
+// checkControlCharacters returns an error for the first string of the
+// documents of y that holds a control character. The strings are taken
+// from the decoded documents, keys included, so that no scalar escapes the
+// check; the scanner only locates the first offending scalar for the
+// message.
+func checkControlCharacters(y []byte) error {
+	docs, err := yamlUnmarshalAll(y)
+	if err != nil {
+		// Left for the decoder to report.
+		return nil
+	}
+	var found rune = -1
+	for _, doc := range docs {
+		if found = controlCharacterIn(doc); found >= 0 {
+			break
+		}
+	}
+	if found < 0 {
+		return nil
+	}
+	for _, t := range scanTokens(y) {
+		if t.kind != quotedToken || y[t.start] != '"' {
+			continue
+		}
+		var s string
+		if err := yaml.Unmarshal(y[t.start:t.end], &s); err != nil {
+			continue
+		}
+		if r := controlCharacterIn(s); r >= 0 {
+			return fmt.Errorf("yaml: line %d, column %d: string contains control character %U",
+				t.line, t.column, r)
+		}
+	}
+	return fmt.Errorf("yaml: string contains control character %U", found)
+}
+
+// controlCharacterIn returns the first control character of the strings of
+// the decoded value v, or -1.
+func controlCharacterIn(v interface{}) rune {
+	switch v := v.(type) {
+	case string:
+		for _, r := range v {
+			if isControlCharacter(r) {
+				return r
+			}
+		}
+	case map[interface{}]interface{}:
+		for k, e := range v {
+			if r := controlCharacterIn(k); r >= 0 {
+				return r
+			}
+			if r := controlCharacterIn(e); r >= 0 {
+				return r
+			}
+		}
+	case []interface{}:
+		for _, e := range v {
+			if r := controlCharacterIn(e); r >= 0 {
+				return r
+			}
+		}
+	}
+	return -1
+}
+
+func isControlCharacter(r rune) bool {
+	return (r < 0x20 && r != '\t' && r != '\n' && r != '\r') || r == 0x7f
+}
@@ -0,0 +1,73 @@
+package yaml
+
+import (
+	"testing"
+)
+
+func TestDisallowControlCharacters(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "plain text",
+			input: "a: b\nc: 'd\\0'\n",
+		},
+		{
+			name:  "tabs and line breaks",
+			input: "a: \"b\\tc\\nd\\r\"\n",
+		},
+		{
+			name:    "NUL escape",
+			input:   "a: b\nc: \"d\\0e\"\n",
+			wantErr: "yaml: line 2, column 4: string contains control character U+0000",
+		},
+		{
+			name:    "escape sequence in a key",
+			input:   "\"\\e[31m\": red\n",
+			wantErr: "yaml: line 1, column 1: string contains control character U+001B",
+		},
+		{
+			name:    "JSON unicode escape",
+			input:   `{"a": ["b", "\u007f"]}`,
+			wantErr: "yaml: line 1, column 13: string contains control character U+007F",
+		},
+		{
+			name:    "compact JSON",
+			input:   `{"a":{"b":"\u0000"}}`,
+			wantErr: "yaml: line 1, column 11: string contains control character U+0000",
+		},
+		{
+			name:    "second document",
+			input:   "a: b\n---\nc: \"\\x01\"\n",
+			wantErr: "yaml: line 3, column 4: string contains control character U+0001",
+		},
+		{
+			name:    "first in document order",
+			input:   "z: \"\\x02\"\na: \"\\x03\"\nm: \"\\x04\"\n",
+			wantErr: "yaml: line 1, column 4: string contains control character U+0002",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			if err := UnmarshalWithOptions([]byte(tt.input), &v); err != nil {
+				t.Fatalf("unexpected error without the option: %v", err)
+			}
+			err := UnmarshalWithOptions([]byte(tt.input), &v, DisallowControlCharacters())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("UnmarshalWithOptions() error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := YAMLToJSONWithOptions([]byte(tt.input), DisallowControlCharacters()); err == nil {
+				t.Errorf("YAMLToJSONWithOptions() accepted the input")
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// TypedDecoder unmarshals YAML into values of a single Go type. The field
+// mappings of that type, and of every struct type reachable from it, are
+// computed once when the decoder is created instead of being looked up on
+// every decode, which pays off when decoding many documents of the same kind.
+// The buffers holding the intermediate JSON of documents are reused by later
+// decodes, as with an Unmarshaler.
+//
+// A TypedDecoder is safe for concurrent use.
+type TypedDecoder struct {
+	typ  reflect.Type
+	opts []JSONOpt
+	conv *converter
+	bufs sync.Pool
+}
+
+// NewDecoderFor returns a TypedDecoder for values of type t. The given JSON
+// options are applied to every decode, as with Unmarshal.
+func NewDecoderFor(t reflect.Type, opts ...JSONOpt) *TypedDecoder {
+	index := map[reflect.Type]*structFields{}
+	indexStructFields(t, index)
+	return &TypedDecoder{
+		typ:  t,
+		opts: opts,
+		conv: &converter{
+			fields: func(t reflect.Type) *structFields {
+				if f, ok := index[t]; ok {
+					return f
+				}
+				// Interface values may hold types the decoder has not seen.
+				return cachedStructFields(t)
+			},
+		},
+	}
+}
+
+// indexStructFields records the fields of every struct type reachable from t
+// in index.
+func indexStructFields(t reflect.Type, index map[reflect.Type]*structFields) {
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		indexStructFields(t.Elem(), index)
+	case reflect.Struct:
+		if _, ok := index[t]; ok {
+			return
+		}
+		f := newStructFields(t)
+		index[t] = f
+		// The type of a field promoted from an embedded struct is found
+		// by its full index.
+		for i := range f.list {
+			indexStructFields(f.list[i].typ, index)
+		}
+	}
+}
+
+// Unmarshal is like the package-level Unmarshal, but o must be a pointer to
+// a value of the decoder's type.
+func (d *TypedDecoder) Unmarshal(y []byte, o interface{}) error {
+	if err := d.checkTarget(o); err != nil {
+		return err
+	}
+	c := d.converter()
+	defer d.release(c)
+	return c.yamlUnmarshal(y, o, false, d.opts...)
+}
+
+// UnmarshalStrict is like the package-level UnmarshalStrict, but o must be a
+// pointer to a value of the decoder's type.
+func (d *TypedDecoder) UnmarshalStrict(y []byte, o interface{}) error {
+	if err := d.checkTarget(o); err != nil {
+		return err
+	}
+	opts := append(d.opts[:len(d.opts):len(d.opts)], DisallowUnknownFields)
+	c := d.converter()
+	defer d.release(c)
+	return c.yamlUnmarshal(y, o, true, opts...)
+}
+
+// converter returns a copy of d.conv with a buffer of its own, so that
+// concurrent decodes do not share one.
+func (d *TypedDecoder) converter() *converter {
+	c := *d.conv
+	if b, ok := d.bufs.Get().(*bytes.Buffer); ok {
+		c.buf = b
+	} else {
+		c.buf = &bytes.Buffer{}
+	}
+	return &c
+}
+
+// release returns the buffer of c for later decodes, unless an unusually
+// large document grew it beyond maxRetainedBuffer.
+func (d *TypedDecoder) release(c *converter) {
+	if c.buf.Cap() <= maxRetainedBuffer {
+		c.buf.Reset()
+		d.bufs.Put(c.buf)
+	}
+}
+
+func (d *TypedDecoder) checkTarget(o interface{}) error {
+	if t := reflect.TypeOf(o); t != reflect.PtrTo(d.typ) {
+		return fmt.Errorf("decoder for %s cannot unmarshal into %v", d.typ, t)
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+type decoderTarget struct {
+	Name  string                   `json:"name"`
+	Items []NestedSlice            `json:"items"`
+	Refs  map[string]*NestedString `json:"refs"`
+}
+
+func TestTypedDecoder(t *testing.T) {
+	d := NewDecoderFor(reflect.TypeOf(decoderTarget{}))
+
+	y := []byte(`
+name: 1
+items:
+- b: 2
+  c: 3
+refs:
+  x:
+    a: 4
+`)
+	e := decoderTarget{
+		Name:  "1",
+		Items: []NestedSlice{{B: "2", C: strPtr("3")}},
+		Refs:  map[string]*NestedString{"x": {A: "4"}},
+	}
+	for i := 0; i < 2; i++ {
+		var got decoderTarget
+		if err := d.Unmarshal(y, &got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(got, e) {
+			t.Errorf("decode %d: expected %+#v, got %+#v", i, e, got)
+		}
+	}
+
+	var got decoderTarget
+	if err := d.UnmarshalStrict([]byte("name: a\nunknown: b\n"), &got); err == nil {
+		t.Error("expected strict decode to fail on unknown field")
+	}
+	if err := d.Unmarshal(y, &NestedString{}); err == nil {
+		t.Error("expected decode into a different type to fail")
+	}
+}
+
+type DecoderEmbedded struct {
+	Inner NestedString `json:"inner"`
+}
+
+func TestTypedDecoderPromotedFields(t *testing.T) {
+	type target struct {
+		Name string `json:"name"`
+		DecoderEmbedded
+	}
+	index := map[reflect.Type]*structFields{}
+	indexStructFields(reflect.TypeOf(target{}), index)
+	// The embedded struct itself is never looked up, only the types of the
+	// fields it promotes.
+	got := map[reflect.Type]bool{}
+	for typ := range index {
+		got[typ] = true
+	}
+	want := map[reflect.Type]bool{reflect.TypeOf(target{}): true, reflect.TypeOf(NestedString{}): true}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("indexed %v, want %v", got, want)
+	}
+
+	d := NewDecoderFor(reflect.TypeOf(target{}))
+	var v target
+	if err := d.Unmarshal([]byte("name: a\ninner:\n  a: 1\n"), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.Inner.A != "1" {
+		t.Errorf("got %+v, want inner.a 1", v)
+	}
+}
+
+// TestTypedDecoderConcurrent decodes with one decoder from several
+// goroutines; run it with -race to check that they do not share buffers.
+func TestTypedDecoderConcurrent(t *testing.T) {
+	d := NewDecoderFor(reflect.TypeOf(decoderTarget{}))
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := strconv.Itoa(i)
+			for j := 0; j < 50; j++ {
+				var got decoderTarget
+				if err := d.Unmarshal([]byte("name: "+name+"\n"), &got); err != nil {
+					errs <- err
+					return
+				}
+				if got.Name != name {
+					errs <- fmt.Errorf("got name %q, want %q", got.Name, name)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
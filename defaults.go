@@ -0,0 +1,114 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ApplyDefaults makes UnmarshalWithOptions set struct fields that are absent
+// from the document to the value of their "default" tag, written in YAML:
+//
+//	Port    int               `json:"port" default:"8080"`
+//	Hosts   []string          `json:"hosts" default:"[localhost]"`
+//	Labels  map[string]string `json:"labels" default:"{app: web}"`
+//
+// Fields present in the document keep their value, even if it is null or a
+// zero value. Defaults also apply inside struct fields that are absent but
+// not pointers, as if they were present and empty, and to an empty
+// document.
+func ApplyDefaults() Option {
+	return func(o *options) {
+		o.applyDefaults = true
+	}
+}
+
+// applyDefaults adds the defaults of the fields of t, and of the structs it
+// contains, that are missing from obj, the JSON-compatible form of a value
+// of type t.
+func (c *converter) applyDefaults(obj interface{}, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return obj, nil
+	}
+
+	var err error
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := obj.(map[string]interface{})
+		if !ok {
+			return obj, nil
+		}
+		fields := c.fields(t)
+		present := map[*field]bool{}
+		for k, v := range m {
+			if f := fields.lookup([]byte(k)); f != nil {
+				present[f] = true
+				if m[k], err = c.applyDefaults(v, f.typ); err != nil {
+					return nil, err
+				}
+			}
+		}
+		for i := range fields.list {
+			f := &fields.list[i]
+			if present[f] {
+				continue
+			}
+			sf := t.FieldByIndex(f.index)
+			if tag, ok := sf.Tag.Lookup("default"); ok {
+				if m[f.name], err = c.parseDefault(tag, sf.Type); err != nil {
+					return nil, fmt.Errorf("invalid default for field %s of %s: %v", sf.Name, t, err)
+				}
+				continue
+			}
+			if sf.Type.Kind() == reflect.Struct {
+				v, err := c.applyDefaults(map[string]interface{}{}, sf.Type)
+				if err != nil {
+					return nil, err
+				}
+				if sub, ok := v.(map[string]interface{}); ok && len(sub) > 0 {
+					m[f.name] = sub
+				}
+			}
+		}
+	case reflect.Map:
+		m, _ := obj.(map[string]interface{})
+		for k, v := range m {
+			if m[k], err = c.applyDefaults(v, t.Elem()); err != nil {
+				return nil, err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		s, _ := obj.([]interface{})
+		for i, v := range s {
+			if s[i], err = c.applyDefaults(v, t.Elem()); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return obj, nil
+}
+
+// parseDefault returns the JSON-compatible form of the default value tag of
+// a field of type t.
+func (c *converter) parseDefault(tag string, t reflect.Type) (interface{}, error) {
+	st := t
+	if st.Kind() == reflect.Ptr {
+		st = st.Elem()
+	}
+	if st.Kind() == reflect.String {
+		// Decoding into a string keeps scalars such as 1.10 as written.
+		var s string
+		err := yaml.Unmarshal([]byte(tag), &s)
+		return s, err
+	}
+	var v interface{}
+	if err := yaml.Unmarshal([]byte(tag), &v); err != nil {
+		return nil, err
+	}
+	target := reflect.New(t).Elem()
+	return c.convertToJSONableObject(v, &target)
+}
@@ -0,0 +1,99 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type DefaultsServer struct {
+	Host    string            `json:"host" default:"localhost"`
+	Port    int               `json:"port" default:"8080"`
+	Debug   bool              `json:"debug" default:"true"`
+	Version string            `json:"version" default:"1.10"`
+	Hosts   []string          `json:"hosts" default:"[a, b]"`
+	Labels  map[string]string `json:"labels" default:"{app: web}"`
+	Timeout *int              `json:"timeout" default:"30"`
+	TLS     DefaultsTLS       `json:"tls"`
+	Proxy   *DefaultsTLS      `json:"proxy"`
+	Name    string            `json:"name"`
+}
+
+type DefaultsTLS struct {
+	Enabled bool   `json:"enabled" default:"true"`
+	Cert    string `json:"cert"`
+}
+
+type DefaultsConfig struct {
+	Servers []DefaultsServer          `json:"servers"`
+	ByName  map[string]DefaultsServer `json:"byName"`
+}
+
+func TestApplyDefaults(t *testing.T) {
+	thirty := 30
+	defaults := DefaultsServer{
+		Host:    "localhost",
+		Port:    8080,
+		Debug:   true,
+		Version: "1.10",
+		Hosts:   []string{"a", "b"},
+		Labels:  map[string]string{"app": "web"},
+		Timeout: &thirty,
+		TLS:     DefaultsTLS{Enabled: true},
+	}
+
+	var s DefaultsServer
+	if err := UnmarshalWithOptions([]byte(""), &s, ApplyDefaults()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(s, defaults) {
+		t.Errorf("got %#v, want %#v", s, defaults)
+	}
+
+	y := []byte(`servers:
+- port: 0
+  debug: false
+  timeout: null
+  hosts: []
+  tls: {cert: c}
+  proxy: {}
+byName:
+  x: {Name: x}
+`)
+	var c DefaultsConfig
+	if err := UnmarshalWithOptions(y, &c, ApplyDefaults()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want0 := defaults
+	want0.Port = 0
+	want0.Debug = false
+	want0.Timeout = nil
+	want0.Hosts = []string{}
+	want0.TLS.Cert = "c"
+	want0.Proxy = &DefaultsTLS{Enabled: true}
+	wantX := defaults
+	wantX.Name = "x"
+	want := DefaultsConfig{
+		Servers: []DefaultsServer{want0},
+		ByName:  map[string]DefaultsServer{"x": wantX},
+	}
+	if !reflect.DeepEqual(c, want) {
+		t.Errorf("got %#v, want %#v", c, want)
+	}
+
+	var plain DefaultsServer
+	if err := UnmarshalWithOptions([]byte("name: x\n"), &plain); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(plain, DefaultsServer{Name: "x"}) {
+		t.Errorf("defaults applied without the option: %#v", plain)
+	}
+
+	var bad struct {
+		N int `json:"n" default:"[1"`
+	}
+	err := UnmarshalWithOptions([]byte("{}"), &bad, ApplyDefaults())
+	if err == nil || !strings.Contains(err.Error(), "invalid default for field N") {
+		t.Errorf("expected invalid default error, got %v", err)
+	}
+}
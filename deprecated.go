@@ -0,0 +1,118 @@
+package yaml
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// deprecatedField is a field tagged deprecated that a document sets.
+type deprecatedField struct {
+	path   string
+	reason string
+}
+
+// warnDeprecated logs the struct fields tagged deprecated that the YAML
+// document y sets when decoded into the value pointed to by o.
+func (c *converter) warnDeprecated(y []byte, o interface{}) {
+	t := reflect.TypeOf(o)
+	if t == nil || !hasDeprecatedFields(t, map[reflect.Type]bool{}) {
+		return
+	}
+	var yamlObj interface{}
+	if err := yaml.Unmarshal(y, &yamlObj); err != nil {
+		return
+	}
+	obj, err := defaultConverter.convertToJSONableObject(yamlObj, nil)
+	if err != nil {
+		return
+	}
+	var found []deprecatedField
+	c.walkFields(t, obj, "", func(path string, f *field) {
+		if f != nil && f.deprecated {
+			found = append(found, deprecatedField{path: path, reason: f.deprecation})
+		}
+	})
+	if len(found) == 0 {
+		return
+	}
+
+	lines := keyLines(y)
+	for _, d := range found {
+		kv := []interface{}{"field", d.path}
+		if line := locatePath(d.path, lines); line > 0 {
+			kv = append(kv, "line", line)
+		}
+		if d.reason != "" {
+			kv = append(kv, "reason", d.reason)
+		}
+		c.opts.logger.Info("deprecated field", kv...)
+	}
+}
+
+// hasDeprecatedFields reports whether a field tagged deprecated can be
+// reached from the type t.
+func hasDeprecatedFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map:
+		return hasDeprecatedFields(t.Elem(), seen)
+	case reflect.Struct:
+		for _, f := range cachedStructFields(t).list {
+			if f.deprecated || hasDeprecatedFields(f.typ, seen) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// walkFields calls fn for each key of the mappings decoded into structs in
+// obj, the JSON-compatible form of the node at path, decoded into a value
+// of type t. fn receives the path of the key and the field it decodes into,
+// or nil if there is none. Keys are visited in sorted order.
+func (c *converter) walkFields(t reflect.Type, obj interface{}, path string, fn func(path string, f *field)) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, _ := obj.(map[string]interface{})
+		fields := c.fields(t)
+		for _, k := range sortedKeys(m) {
+			p := joinPath(path, k)
+			f := fields.lookup([]byte(c.mapKey(t, k)))
+			fn(p, f)
+			if f != nil {
+				c.walkFields(f.typ, m[k], p, fn)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		s, _ := obj.([]interface{})
+		for i, e := range s {
+			c.walkFields(t.Elem(), e, path+"["+strconv.Itoa(i)+"]", fn)
+		}
+	case reflect.Map:
+		m, _ := obj.(map[string]interface{})
+		for _, k := range sortedKeys(m) {
+			c.walkFields(t.Elem(), m[k], joinPath(path, k), fn)
+		}
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
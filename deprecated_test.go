@@ -0,0 +1,65 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWarnDeprecated(t *testing.T) {
+	type container struct {
+		Image string `json:"image"`
+		Pull  string `json:"pull" deprecated:"use imagePullPolicy"`
+	}
+	type spec struct {
+		Replicas   int         `json:"replicas" deprecated:"use replicaCount instead"`
+		Count      int         `json:"replicaCount" yamlalias:"count"`
+		Legacy     *string     `json:"legacy" deprecated:""`
+		Containers []container `json:"containers"`
+	}
+	y := []byte(`replicas: 2
+legacy: null
+containers:
+- image: a
+- image: b
+  pull: always
+`)
+	var got spec
+	l := &recordingLogger{}
+	if err := UnmarshalWithOptions(y, &got, WithLogger(l)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"info: deprecated field [field containers[1].pull line 6 reason use imagePullPolicy]",
+		"info: deprecated field [field legacy line 2]",
+		"info: deprecated field [field replicas line 1 reason use replicaCount instead]",
+	}
+	if !reflect.DeepEqual(l.entries, want) {
+		t.Errorf("logged:\n%q\nwant:\n%q", l.entries, want)
+	}
+	if got.Replicas != 2 || got.Containers[1].Pull != "always" {
+		t.Errorf("deprecated fields were not decoded: %+v", got)
+	}
+
+	l = &recordingLogger{}
+	if err := UnmarshalWithOptions([]byte("count: 1\ncontainers: [{image: a}]\n"), &got, WithLogger(l)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(l.entries) != 0 {
+		t.Errorf("logged %q for a document without deprecated fields", l.entries)
+	}
+}
+
+func TestHasDeprecatedFields(t *testing.T) {
+	type node struct {
+		Children []*node `json:"children"`
+	}
+	type old struct {
+		A int `json:"a" deprecated:"gone"`
+	}
+	if hasDeprecatedFields(reflect.TypeOf(&node{}), map[reflect.Type]bool{}) {
+		t.Errorf("hasDeprecatedFields(node) = true")
+	}
+	if !hasDeprecatedFields(reflect.TypeOf(map[string][]old{}), map[reflect.Type]bool{}) {
+		t.Errorf("hasDeprecatedFields(map[string][]old) = false")
+	}
+}
@@ -0,0 +1,222 @@
+package yaml
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ChangeType is the kind of a Change.
+type ChangeType int
+
+const (
+	// Added is a value present only in the new document.
+	Added ChangeType = iota + 1
+	// Removed is a value present only in the old document.
+	Removed
+	// Modified is a value present in both documents with different
+	// contents.
+	Modified
+)
+
+func (t ChangeType) String() string {
+	switch t {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	}
+	return "ChangeType(" + strconv.Itoa(int(t)) + ")"
+}
+
+// Change is a difference between two YAML documents. See Diff.
+type Change struct {
+	Type ChangeType
+	// Path locates the value, joining mapping keys with "." and appending
+	// "[i]" for sequence entries. It is empty for the document's root.
+	Path string
+	// From and To are the JSON-compatible old and new values: From is nil
+	// for Added and To for Removed. Both are nil if Redacted is set.
+	From, To interface{}
+	// Redacted reports that the value is at a path selected by RedactPaths
+	// and that its contents are withheld.
+	Redacted bool
+}
+
+// String formats c as a line of a drift report, such as
+// `~ spec.replicas: 1 -> 3` or `+ data.password: <redacted>`.
+func (c Change) String() string {
+	path := c.Path
+	if path == "" {
+		path = "<root>"
+	}
+	switch {
+	case c.Redacted:
+		return changeSign(c.Type) + " " + path + ": <redacted>"
+	case c.Type == Added:
+		return "+ " + path + ": " + changeValue(c.To)
+	case c.Type == Removed:
+		return "- " + path + ": " + changeValue(c.From)
+	}
+	return "~ " + path + ": " + changeValue(c.From) + " -> " + changeValue(c.To)
+}
+
+// changeSign returns the sign marking changes of type t in a report.
+func changeSign(t ChangeType) string {
+	switch t {
+	case Added:
+		return "+"
+	case Removed:
+		return "-"
+	}
+	return "~"
+}
+
+// changeValue formats a value of a Change in JSON.
+func changeValue(v interface{}) string {
+	j, err := json.Marshal(v)
+	if err != nil {
+		return "<invalid>"
+	}
+	return string(j)
+}
+
+// RedactPaths makes Diff withhold the values at paths matching one of paths,
+// such as "data.*" for the values of a Secret, while still reporting whether
+// they were added, removed or modified, so that drift reports can be shared
+// without leaking secrets. Paths follow the syntax of EncryptFields.
+func RedactPaths(paths ...string) Option {
+	return func(o *options) {
+		o.redactPaths = paths
+	}
+}
+
+// Diff returns the differences between the first YAML documents of a and b,
+// in path order. Mappings are compared key by key and sequences entry by
+// entry; any other difference, including one of type, is reported as a
+// modification of the whole value.
+func Diff(a, b []byte, opts ...Option) ([]Change, error) {
+	o := newOptions(opts...)
+	from, err := diffObject(a)
+	if err != nil {
+		return nil, o.sourceError(err)
+	}
+	to, err := diffObject(b)
+	if err != nil {
+		return nil, o.sourceError(err)
+	}
+	var changes []Change
+	diffValues(from, to, "", o, &changes)
+	return changes, nil
+}
+
+// diffObject returns the JSON-compatible form of the first document of y.
+func diffObject(y []byte) (interface{}, error) {
+	var yamlObj interface{}
+	if err := yaml.Unmarshal(y, &yamlObj); err != nil {
+		return nil, err
+	}
+	return defaultConverter.convertToJSONableObject(yamlObj, nil)
+}
+
+// diffValues appends the differences between the values from and to found
+// at path to changes.
+func diffValues(from, to interface{}, path string, o *options, changes *[]Change) {
+	if reflect.DeepEqual(from, to) {
+		return
+	}
+	if matchAnyPath(o.redactPaths, path) {
+		*changes = append(*changes, Change{Type: Modified, Path: path, Redacted: true})
+		return
+	}
+
+	switch f := from.(type) {
+	case map[string]interface{}:
+		if t, ok := to.(map[string]interface{}); ok {
+			keys := make([]string, 0, len(f)+len(t))
+			for k := range f {
+				keys = append(keys, k)
+			}
+			for k := range t {
+				if _, ok := f[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+			for _, k := range keys {
+				fv, inFrom := f[k]
+				tv, inTo := t[k]
+				p := joinPath(path, k)
+				switch {
+				case !inFrom:
+					addChange(Change{Type: Added, Path: p, To: tv}, o, changes)
+				case !inTo:
+					addChange(Change{Type: Removed, Path: p, From: fv}, o, changes)
+				default:
+					diffValues(fv, tv, p, o, changes)
+				}
+			}
+			return
+		}
+	case []interface{}:
+		if t, ok := to.([]interface{}); ok {
+			for i := 0; i < len(f) || i < len(t); i++ {
+				p := path + "[" + strconv.Itoa(i) + "]"
+				switch {
+				case i >= len(f):
+					addChange(Change{Type: Added, Path: p, To: t[i]}, o, changes)
+				case i >= len(t):
+					addChange(Change{Type: Removed, Path: p, From: f[i]}, o, changes)
+				default:
+					diffValues(f[i], t[i], p, o, changes)
+				}
+			}
+			return
+		}
+	}
+	*changes = append(*changes, Change{Type: Modified, Path: path, From: from, To: to})
+}
+
+// addChange appends c, an addition or removal, to changes, redacting it if
+// its path, or the path of a value within it, is selected by RedactPaths.
+func addChange(c Change, o *options, changes *[]Change) {
+	v := c.From
+	if c.Type == Added {
+		v = c.To
+	}
+	if matchAnyPath(o.redactPaths, c.Path) {
+		*changes = append(*changes, Change{Type: c.Type, Path: c.Path, Redacted: true})
+		return
+	}
+	if containsRedacted(v, c.Path, o) {
+		c.From, c.To, c.Redacted = nil, nil, true
+	}
+	*changes = append(*changes, c)
+}
+
+// containsRedacted reports whether a value within v, found at path, is at a
+// path selected by RedactPaths.
+func containsRedacted(v interface{}, path string, o *options) bool {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			p := joinPath(path, k)
+			if matchAnyPath(o.redactPaths, p) || containsRedacted(e, p, o) {
+				return true
+			}
+		}
+	case []interface{}:
+		for i, e := range v {
+			p := path + "[" + strconv.Itoa(i) + "]"
+			if matchAnyPath(o.redactPaths, p) || containsRedacted(e, p, o) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	from := []byte(`kind: Secret
+metadata:
+  name: creds
+  labels: {app: web}
+data:
+  password: aHVudGVyMg==
+  token: c2VjcmV0
+  user: YWRtaW4=
+ports: [80, 443]
+`)
+	to := []byte(`kind: Secret
+metadata:
+  name: creds
+  labels: {app: web, tier: front}
+data:
+  password: c3dvcmRmaXNo
+  token: c2VjcmV0
+  cert: LS0tLS1CRUdJTg==
+ports: [8080]
+stringData:
+  key: value
+`)
+
+	changes, err := Diff(from, to)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Change{
+		{Type: Added, Path: "data.cert", To: "LS0tLS1CRUdJTg=="},
+		{Type: Modified, Path: "data.password", From: "aHVudGVyMg==", To: "c3dvcmRmaXNo"},
+		{Type: Removed, Path: "data.user", From: "YWRtaW4="},
+		{Type: Added, Path: "metadata.labels.tier", To: "front"},
+		{Type: Modified, Path: "ports[0]", From: 80, To: 8080},
+		{Type: Removed, Path: "ports[1]", From: 443},
+		{Type: Added, Path: "stringData", To: map[string]interface{}{"key": "value"}},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("got %#v, want %#v", changes, want)
+	}
+
+	changes, err = Diff(from, to, RedactPaths("data.*", "stringData.*"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var lines []string
+	for _, c := range changes {
+		lines = append(lines, c.String())
+	}
+	got := strings.Join(lines, "\n")
+	wantReport := `+ data.cert: <redacted>
+~ data.password: <redacted>
+- data.user: <redacted>
++ metadata.labels.tier: "front"
+~ ports[0]: 80 -> 8080
+- ports[1]: 443
++ stringData: <redacted>`
+	if got != wantReport {
+		t.Errorf("got\n%s\nwant\n%s", got, wantReport)
+	}
+	if strings.Contains(got, "c3dvcmRmaXNo") || strings.Contains(got, "value") {
+		t.Error("report leaks a redacted value")
+	}
+	for _, c := range changes {
+		if c.Redacted && (c.From != nil || c.To != nil) {
+			t.Errorf("redacted change %s holds values", c.Path)
+		}
+	}
+
+	changes, err = Diff([]byte("a: 1\n"), []byte("- 1\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changes) != 1 || changes[0].String() != `~ <root>: {"a":1} -> [1]` {
+		t.Errorf("got %v", changes)
+	}
+
+	if changes, err := Diff(from, from, RedactPaths("data.*")); err != nil || len(changes) != 0 {
+		t.Errorf("Diff of identical documents = %v, %v", changes, err)
+	}
+	if _, err := Diff([]byte("a: [1\n"), to, SourceName("old.yaml")); err == nil || !strings.Contains(err.Error(), "old.yaml") {
+		t.Errorf("expected an error naming the source, got %v", err)
+	}
+}
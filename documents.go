@@ -0,0 +1,261 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+)
+
+// SplitDocuments splits the YAML stream y into its documents, without
+// parsing them. Each document is returned as written, comments included, but
+// without the "---" and "..." markers delimiting it. A document preceded by
+// directives (such as "%YAML 1.1") keeps them along with its "---" marker, as
+// the directives would be invalid on their own.
+//
+// A document explicitly started by "---" is returned even if it is empty,
+// unless changed with EmptyDocuments; blank or comment-only text before the
+// first "---" or after the last "..." is not a document.
+func SplitDocuments(y []byte, opts ...Option) ([][]byte, error) {
+	o := newOptions(opts...)
+	y, err := o.transcodeStream(y)
+	if err != nil {
+		return nil, err
+	}
+	var docs [][]byte
+	for _, d := range splitDocuments(y) {
+		if d.empty {
+			switch o.emptyDocuments {
+			case SkipEmptyDocuments:
+				continue
+			case NullEmptyDocuments:
+				docs = append(docs, []byte("null"))
+				continue
+			case RejectEmptyDocuments:
+				return nil, emptyDocumentError(y, d)
+			}
+		}
+		docs = append(docs, y[d.start:d.end])
+	}
+	return docs, nil
+}
+
+// EmptyDocumentPolicy tells multi-document functions what to do with empty
+// documents: those with nothing but blank lines and comments, such as the
+// one started by a trailing "---".
+type EmptyDocumentPolicy int
+
+const (
+	// SkipEmptyDocuments drops empty documents silently.
+	SkipEmptyDocuments EmptyDocumentPolicy = iota + 1
+	// NullEmptyDocuments replaces empty documents with a "null" document,
+	// which is what they decode to, so that documents keep their position.
+	NullEmptyDocuments
+	// RejectEmptyDocuments makes empty documents an error.
+	RejectEmptyDocuments
+)
+
+// EmptyDocuments sets how SplitDocuments and ComposeBundle handle empty
+// documents. By default SplitDocuments returns them as written and
+// ComposeBundle skips them.
+func EmptyDocuments(p EmptyDocumentPolicy) Option {
+	return func(o *options) {
+		o.emptyDocuments = p
+	}
+}
+
+// emptyDocumentError returns the error for the empty document d of the YAML
+// stream y.
+func emptyDocumentError(y []byte, d documentRange) error {
+	line := 1 + bytes.Count(y[:d.markerStart], []byte("\n"))
+	return fmt.Errorf("yaml: line %d: empty document", line)
+}
+
+// documentRange is the extent of a document within a YAML stream.
+type documentRange struct {
+	// start and end delimit the document's content; markerStart is the
+	// offset of its "---" marker, or of its directives, or start if it has
+	// neither.
+	markerStart int
+	start       int
+	end         int
+	// explicit is true for documents started with a "---" marker.
+	explicit bool
+	// empty is true for documents with nothing but blank lines and
+	// comments.
+	empty bool
+}
+
+// splitDocuments returns the ranges of the documents of the YAML stream y.
+//
+// Document markers are only recognized at the start of a line, where they
+// cannot be part of any well-formed scalar, so no parsing is required.
+func splitDocuments(y []byte) []documentRange {
+	var docs []documentRange
+	cur := documentRange{}
+	// content is true once the current document has anything other than
+	// blank lines and comments.
+	content := false
+	// directives is the offset of the directives preceding the next
+	// document, or -1.
+	directives := -1
+
+	finish := func(end int) {
+		if cur.explicit || content {
+			cur.end = end
+			cur.empty = !content
+			docs = append(docs, cur)
+		}
+		content = false
+	}
+
+	for pos := 0; pos < len(y); {
+		end := lineEnd(y, pos)
+		next := end
+		if next < len(y) {
+			next++
+		}
+		line := y[pos:end]
+
+		switch {
+		case isMarker(line, "---"):
+			finish(pos)
+			cur = documentRange{markerStart: pos, start: next, explicit: true}
+			if rest := bytes.TrimSpace(line[3:]); len(rest) > 0 {
+				// Content, such as a block scalar header, on the marker line.
+				cur.start = pos + 4
+				content = rest[0] != '#'
+			}
+			if directives >= 0 {
+				cur.markerStart, cur.start = directives, directives
+				directives = -1
+			}
+		case isMarker(line, "..."):
+			finish(pos)
+			cur = documentRange{markerStart: next, start: next}
+		case len(line) > 0 && line[0] == '%' && !cur.explicit && !content:
+			if directives < 0 {
+				directives = pos
+			}
+		default:
+			if t := bytes.TrimSpace(line); len(t) > 0 && t[0] != '#' {
+				content = true
+			}
+		}
+		pos = next
+	}
+	finish(len(y))
+	return docs
+}
+
+// isMarker reports whether line starts with the document marker m.
+func isMarker(line []byte, m string) bool {
+	return bytes.HasPrefix(line, []byte(m)) && isBlankOrEnd(line, len(m))
+}
+
+// SingleDocument makes UnmarshalWithOptions and YAMLToJSONWithOptions reject
+// a stream with more than one document, rather than silently ignoring all
+// but the first, which usually means files were concatenated by mistake.
+// Empty documents after the first, such as one started by a trailing "---",
+// are allowed.
+func SingleDocument() Option {
+	return func(o *options) {
+		o.singleDocument = true
+	}
+}
+
+// checkSingleDocument returns an error if a document other than the first
+// one of the YAML stream y has content.
+func checkSingleDocument(y []byte) error {
+	docs := splitDocuments(y)
+	for i := 1; i < len(docs); i++ {
+		if !docs[i].empty {
+			line := 1 + bytes.Count(y[:docs[i].markerStart], []byte("\n"))
+			return fmt.Errorf("yaml: line %d: found a document after the first one, expected a single document", line)
+		}
+	}
+	return nil
+}
+
+// Document describes a document of a YAML stream, as returned by
+// ScanDocuments.
+type Document struct {
+	// Index is the position of the document in the stream, counting from 0
+	// and including any empty documents skipped.
+	Index int
+	// Source names the stream, as set with SourceName.
+	Source string
+	// Start and End delimit the document in the stream, directives and
+	// "---" marker included, and Line is the line it starts on, 1-based.
+	Start int
+	End   int
+	Line  int
+	// Directives holds the directives preceding the document, such as
+	// "%YAML 1.1".
+	Directives []string
+	// Anchors lists the names of the anchors defined in the document, in
+	// order of definition.
+	Anchors []string
+	// Content is the document as returned by SplitDocuments.
+	Content []byte
+}
+
+// String describes the position of d for use in messages, such as
+// "document 3 of x.yaml".
+func (d Document) String() string {
+	s := "document " + strconv.Itoa(d.Index+1)
+	if d.Source != "" {
+		s += " of " + d.Source
+	}
+	return s
+}
+
+// ScanDocuments is like SplitDocuments but returns each document along with
+// its position in the stream and its metadata.
+func ScanDocuments(y []byte, opts ...Option) ([]Document, error) {
+	o := newOptions(opts...)
+	y, err := o.transcodeStream(y)
+	if err != nil {
+		return nil, err
+	}
+	tokens := scanTokens(y)
+	var docs []Document
+	next := 0
+	line, counted := 1, 0
+	for i, d := range splitDocuments(y) {
+		line += bytes.Count(y[counted:d.markerStart], []byte("\n"))
+		counted = d.markerStart
+		content := y[d.start:d.end]
+		if d.empty {
+			switch o.emptyDocuments {
+			case SkipEmptyDocuments:
+				continue
+			case NullEmptyDocuments:
+				content = []byte("null")
+			case RejectEmptyDocuments:
+				return nil, emptyDocumentError(y, d)
+			}
+		}
+		doc := Document{
+			Index:   i,
+			Source:  o.sourceName,
+			Start:   d.markerStart,
+			End:     d.end,
+			Line:    line,
+			Content: content,
+		}
+		for pos := d.markerStart; pos < d.end && y[pos] == '%'; {
+			end := lineEnd(y, pos)
+			doc.Directives = append(doc.Directives, string(bytes.TrimRight(y[pos:end], " \t\r")))
+			pos = end + 1
+		}
+		// Tokens are in input order, so each document resumes the search
+		// where the previous one left off.
+		for ; next < len(tokens) && tokens[next].start < d.end; next++ {
+			if t := tokens[next]; t.kind == anchorToken && t.start >= d.start && t.end <= d.end {
+				doc.Anchors = append(doc.Anchors, string(y[t.start+1:t.end]))
+			}
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
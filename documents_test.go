@@ -0,0 +1,185 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []string
+	}{
+		{
+			name:  "single implicit document",
+			input: "# c\na: 1\n",
+			want:  []string{"# c\na: 1\n"},
+		},
+		{
+			name:  "separators",
+			input: "---\na: 1\n---\nb: 2\n",
+			want:  []string{"a: 1\n", "b: 2\n"},
+		},
+		{
+			name:  "implicit first document",
+			input: "a: 1\n--- # second\nb: 2\n...\n# trailing\n",
+			want:  []string{"a: 1\n", "# second\nb: 2\n"},
+		},
+		{
+			name:  "empty explicit documents",
+			input: "---\n---\na: 1\n---\n",
+			want:  []string{"", "a: 1\n", ""},
+		},
+		{
+			name:  "content on the marker line",
+			input: "--- |\n  text\n---   >\n  more\n",
+			want:  []string{"|\n  text\n", "  >\n  more\n"},
+		},
+		{
+			name:  "markers inside content",
+			input: "a: |\n  ---x\n  ...\nb: '---'\n",
+			want:  []string{"a: |\n  ---x\n  ...\nb: '---'\n"},
+		},
+		{
+			name:  "directives",
+			input: "a: 1\n...\n%YAML 1.1\n---\nb: 2\n",
+			want:  []string{"a: 1\n", "%YAML 1.1\n---\nb: 2\n"},
+		},
+		{
+			name:  "CRLF",
+			input: "a: 1\r\n---\r\nb: 2\r\n",
+			want:  []string{"a: 1\r\n", "b: 2\r\n"},
+		},
+		{
+			name:  "blank stream",
+			input: "\n# only a comment\n",
+			want:  nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			docs, err := SplitDocuments([]byte(tt.input))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, d := range docs {
+				got = append(got, string(d))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitDocuments() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSingleDocument(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{name: "single", input: "a: 1\n"},
+		{name: "explicit single", input: "---\na: 1\n...\n"},
+		{name: "trailing marker", input: "a: 1\n---\n# nothing\n"},
+		{
+			name:    "two documents",
+			input:   "a: 1\n---\nb: 2\n",
+			wantErr: "yaml: line 2: found a document after the first one, expected a single document",
+		},
+		{
+			name:    "empty first document",
+			input:   "---\n---\nb: 2\n",
+			wantErr: "yaml: line 2: found a document after the first one, expected a single document",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			errs := []error{UnmarshalWithOptions([]byte(tt.input), &v, SingleDocument())}
+			_, err := YAMLToJSONWithOptions([]byte(tt.input), SingleDocument())
+			errs = append(errs, err)
+			for _, err := range errs {
+				if tt.wantErr == "" {
+					if err != nil {
+						t.Errorf("unexpected error: %v", err)
+					}
+				} else if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyDocuments(t *testing.T) {
+	input := []byte("a: 1\n---\n# comment only\n---\nb: 2\n---\n")
+	tests := []struct {
+		policy  EmptyDocumentPolicy
+		want    []string
+		wantErr string
+	}{
+		{policy: 0, want: []string{"a: 1\n", "# comment only\n", "b: 2\n", ""}},
+		{policy: SkipEmptyDocuments, want: []string{"a: 1\n", "b: 2\n"}},
+		{policy: NullEmptyDocuments, want: []string{"a: 1\n", "null", "b: 2\n", "null"}},
+		{policy: RejectEmptyDocuments, wantErr: "yaml: line 2: empty document"},
+	}
+	for _, tt := range tests {
+		docs, err := SplitDocuments(input, EmptyDocuments(tt.policy))
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("policy %d: got error %v, want %s", tt.policy, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("policy %d: unexpected error: %v", tt.policy, err)
+		}
+		var got []string
+		for _, d := range docs {
+			got = append(got, string(d))
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("policy %d: SplitDocuments() = %q, want %q", tt.policy, got, tt.want)
+		}
+	}
+}
+
+func TestScanDocuments(t *testing.T) {
+	input := "a: &x 1\nb: *x\n---\n...\n%YAML 1.1\n%TAG ! tag:example.com,2000:\n---\nc: &y [&z 2]\n"
+	docs, err := ScanDocuments([]byte(input), SourceName("x.yaml"), EmptyDocuments(SkipEmptyDocuments))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []Document{
+		{
+			Index:   0,
+			Source:  "x.yaml",
+			Start:   0,
+			End:     14,
+			Line:    1,
+			Anchors: []string{"x"},
+			Content: []byte("a: &x 1\nb: *x\n"),
+		},
+		{
+			Index:      2,
+			Source:     "x.yaml",
+			Start:      22,
+			End:        len(input),
+			Line:       5,
+			Directives: []string{"%YAML 1.1", "%TAG ! tag:example.com,2000:"},
+			Anchors:    []string{"y", "z"},
+			Content:    []byte(input[22:]),
+		},
+	}
+	if !reflect.DeepEqual(docs, want) {
+		t.Errorf("ScanDocuments() = %#v, want %#v", docs, want)
+	}
+	if s := docs[1].String(); s != "document 3 of x.yaml" {
+		t.Errorf("String() = %q, want %q", s, "document 3 of x.yaml")
+	}
+	if s := (Document{Index: 0}).String(); s != "document 1" {
+		t.Errorf("String() = %q, want %q", s, "document 1")
+	}
+}
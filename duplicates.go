@@ -0,0 +1,71 @@
+package yaml
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DuplicateDocuments is a set of documents of a YAML stream that describe
+// the same resource, or that have the same content.
+type DuplicateDocuments struct {
+	// Resource identifies the resource the documents describe, as
+	// "apiVersion kind namespace/name", or is empty for documents without
+	// a kind and name that have the same content.
+	Resource string
+	// Documents lists the duplicates in stream order.
+	Documents []Document
+}
+
+// FindDuplicateDocuments returns the sets of documents of the YAML stream y
+// that share apiVersion, kind, namespace and name, which is a frequent source
+// of confusing apply behavior in generated bundles, in the order of their
+// first document. Documents without a kind and name are compared by content
+// instead, disregarding key order, formatting and comments. Null and empty
+// documents are ignored.
+//
+// SourceName sets the Source of the returned documents. An error is returned
+// if a document is not valid YAML.
+func FindDuplicateDocuments(y []byte, opts ...Option) ([]DuplicateDocuments, error) {
+	docs, err := ScanDocuments(y, opts...)
+	if err != nil {
+		return nil, err
+	}
+	var dups []DuplicateDocuments
+	groups := map[string]int{}
+	for _, d := range docs {
+		var obj interface{}
+		if err := yaml.Unmarshal(d.Content, &obj); err != nil {
+			return nil, fmt.Errorf("%s: %v", d, err)
+		}
+		if obj == nil {
+			continue
+		}
+		var key, resource string
+		if id := identify(obj); id.Kind != "" && id.Name != "" {
+			resource = id.String()
+			key = "resource " + resource
+		} else {
+			j, err := YAMLToJSON(d.Content)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %v", d, err)
+			}
+			key = "content " + string(j)
+		}
+		i, ok := groups[key]
+		if !ok {
+			i = len(dups)
+			groups[key] = i
+			dups = append(dups, DuplicateDocuments{Resource: resource})
+		}
+		dups[i].Documents = append(dups[i].Documents, d)
+	}
+
+	out := dups[:0]
+	for _, g := range dups {
+		if len(g.Documents) > 1 {
+			out = append(out, g)
+		}
+	}
+	return out, nil
+}
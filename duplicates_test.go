@@ -0,0 +1,65 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindDuplicateDocuments(t *testing.T) {
+	y := []byte(`apiVersion: v1
+kind: ConfigMap
+metadata: {name: app, namespace: prod}
+---
+# unrelated
+a: 1
+b: [x]
+---
+apiVersion: v1
+kind: ConfigMap
+metadata: {name: app, namespace: dev}
+---
+---
+{b: [x], a: 1}
+---
+apiVersion: v1
+kind: ConfigMap
+metadata:
+  namespace: prod
+  name: app
+data: {changed: "true"}
+---
+a: 2
+`)
+	got, err := FindDuplicateDocuments(y, SourceName("bundle.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	type group struct {
+		Resource string
+		Indexes  []int
+		Lines    []int
+	}
+	var groups []group
+	for _, g := range got {
+		var indexes, lines []int
+		for _, d := range g.Documents {
+			if d.Source != "bundle.yaml" {
+				t.Errorf("document source = %q", d.Source)
+			}
+			indexes = append(indexes, d.Index)
+			lines = append(lines, d.Line)
+		}
+		groups = append(groups, group{g.Resource, indexes, lines})
+	}
+	want := []group{
+		{"v1 ConfigMap prod/app", []int{0, 5}, []int{1, 15}},
+		{"", []int{1, 4}, []int{4, 13}},
+	}
+	if !reflect.DeepEqual(groups, want) {
+		t.Errorf("FindDuplicateDocuments() = %+v, want %+v", groups, want)
+	}
+
+	if _, err := FindDuplicateDocuments([]byte("a: 1\n---\na: [\n")); err == nil {
+		t.Errorf("expected an error for invalid YAML")
+	}
+}
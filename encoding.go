@@ -0,0 +1,141 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// Encoding is a character encoding of a YAML stream. See DetectEncoding.
+type Encoding int
+
+const (
+	UTF8 Encoding = iota
+	UTF16LE
+	UTF16BE
+	UTF32LE
+	UTF32BE
+)
+
+func (e Encoding) String() string {
+	switch e {
+	case UTF8:
+		return "UTF-8"
+	case UTF16LE:
+		return "UTF-16LE"
+	case UTF16BE:
+		return "UTF-16BE"
+	case UTF32LE:
+		return "UTF-32LE"
+	case UTF32BE:
+		return "UTF-32BE"
+	}
+	return "Encoding(" + strconv.Itoa(int(e)) + ")"
+}
+
+// DetectEncoding returns the encoding of the YAML stream data, detected as
+// the YAML specification prescribes: from its byte order mark or, if it has
+// none, from the null bytes of its first character, which is ASCII in any
+// YAML stream. bom reports whether data starts with a byte order mark.
+//
+// Many YAML consumers, including go-yaml, reject UTF-32 input, and UTF-16
+// input without a byte order mark; a byte order mark is itself rejected by
+// some JSON consumers. Tools can use DetectEncoding to warn about such
+// files, and TranscodeInput to accept them.
+func DetectEncoding(data []byte) (enc Encoding, bom bool) {
+	switch {
+	case bytes.HasPrefix(data, []byte{0x00, 0x00, 0xfe, 0xff}):
+		return UTF32BE, true
+	case len(data) >= 4 && data[0] == 0 && data[1] == 0 && data[2] == 0:
+		return UTF32BE, false
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe, 0x00, 0x00}):
+		return UTF32LE, true
+	case len(data) >= 4 && data[1] == 0 && data[2] == 0 && data[3] == 0:
+		return UTF32LE, false
+	case bytes.HasPrefix(data, []byte{0xfe, 0xff}):
+		return UTF16BE, true
+	case len(data) >= 2 && data[0] == 0:
+		return UTF16BE, false
+	case bytes.HasPrefix(data, []byte{0xff, 0xfe}):
+		return UTF16LE, true
+	case len(data) >= 2 && data[1] == 0:
+		return UTF16LE, false
+	case bytes.HasPrefix(data, []byte{0xef, 0xbb, 0xbf}):
+		return UTF8, true
+	}
+	return UTF8, false
+}
+
+// TranscodeInput makes the decoding functions that take options accept
+// UTF-16 and UTF-32 input, with or without a byte order mark, by converting
+// it to UTF-8 before decoding, and drop the byte order mark of UTF-8 input,
+// which would otherwise shift the columns of error messages and options
+// that inspect the input. The encoding is detected by DetectEncoding.
+//
+// Multi-document functions, such as UnmarshalDocuments and ScanDocuments,
+// convert the whole stream before splitting it, so the offsets they report
+// are those of the UTF-8 text.
+func TranscodeInput() Option {
+	return func(o *options) {
+		o.transcodeInput = true
+	}
+}
+
+// transcodeStream converts the YAML stream y to UTF-8 if o asks for it, for
+// the functions that split y into documents before decoding them.
+func (o *options) transcodeStream(y []byte) ([]byte, error) {
+	if !o.transcodeInput {
+		return y, nil
+	}
+	return transcode(y)
+}
+
+// transcode converts the YAML stream y to UTF-8 without a byte order mark.
+func transcode(y []byte) ([]byte, error) {
+	enc, bom := DetectEncoding(y)
+	switch enc {
+	case UTF8:
+		if bom {
+			return y[3:], nil
+		}
+		return y, nil
+	case UTF16LE, UTF16BE:
+		if bom {
+			y = y[2:]
+		}
+		if len(y)%2 != 0 {
+			return nil, fmt.Errorf("yaml: invalid %s input: odd number of bytes", enc)
+		}
+		order := binary.ByteOrder(binary.LittleEndian)
+		if enc == UTF16BE {
+			order = binary.BigEndian
+		}
+		units := make([]uint16, len(y)/2)
+		for i := range units {
+			units[i] = order.Uint16(y[2*i:])
+		}
+		return []byte(string(utf16.Decode(units))), nil
+	}
+	if bom {
+		y = y[4:]
+	}
+	if len(y)%4 != 0 {
+		return nil, fmt.Errorf("yaml: invalid %s input: length not a multiple of 4", enc)
+	}
+	order := binary.ByteOrder(binary.LittleEndian)
+	if enc == UTF32BE {
+		order = binary.BigEndian
+	}
+	out := make([]byte, 0, len(y)/4)
+	for i := 0; i < len(y); i += 4 {
+		r := rune(order.Uint32(y[i:]))
+		if !utf8.ValidRune(r) {
+			return nil, fmt.Errorf("yaml: invalid %s input: invalid character %#x at offset %d", enc, uint32(r), i)
+		}
+		out = append(out, string(r)...)
+	}
+	return out, nil
+}
@@ -0,0 +1,121 @@
+package yaml
+
+import (
+	"encoding/binary"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+func encodeUTF16(s string, order binary.ByteOrder, bom bool) []byte {
+	units := utf16.Encode([]rune(s))
+	if bom {
+		units = append([]uint16{0xfeff}, units...)
+	}
+	b := make([]byte, 2*len(units))
+	for i, u := range units {
+		order.PutUint16(b[2*i:], u)
+	}
+	return b
+}
+
+func encodeUTF32(s string, order binary.ByteOrder, bom bool) []byte {
+	runes := []rune(s)
+	if bom {
+		runes = append([]rune{0xfeff}, runes...)
+	}
+	b := make([]byte, 4*len(runes))
+	for i, r := range runes {
+		order.PutUint32(b[4*i:], uint32(r))
+	}
+	return b
+}
+
+func TestDetectEncoding(t *testing.T) {
+	const doc = "name: café\n"
+	cases := []struct {
+		data []byte
+		enc  Encoding
+		bom  bool
+	}{
+		{[]byte(doc), UTF8, false},
+		{append([]byte{0xef, 0xbb, 0xbf}, doc...), UTF8, true},
+		{encodeUTF16(doc, binary.LittleEndian, false), UTF16LE, false},
+		{encodeUTF16(doc, binary.LittleEndian, true), UTF16LE, true},
+		{encodeUTF16(doc, binary.BigEndian, false), UTF16BE, false},
+		{encodeUTF16(doc, binary.BigEndian, true), UTF16BE, true},
+		{encodeUTF32(doc, binary.LittleEndian, false), UTF32LE, false},
+		{encodeUTF32(doc, binary.LittleEndian, true), UTF32LE, true},
+		{encodeUTF32(doc, binary.BigEndian, false), UTF32BE, false},
+		{encodeUTF32(doc, binary.BigEndian, true), UTF32BE, true},
+		{nil, UTF8, false},
+	}
+	for _, tc := range cases {
+		enc, bom := DetectEncoding(tc.data)
+		if enc != tc.enc || bom != tc.bom {
+			t.Errorf("DetectEncoding(% x) = %v, %v, want %v, %v", tc.data, enc, bom, tc.enc, tc.bom)
+			continue
+		}
+		var got map[string]string
+		if err := UnmarshalWithOptions(tc.data, &got, TranscodeInput()); err != nil {
+			t.Errorf("%v input: unexpected error: %v", tc.enc, err)
+			continue
+		}
+		if want := map[string]string{"name": "café"}; tc.data != nil && !reflect.DeepEqual(got, want) {
+			t.Errorf("%v input: got %v, want %v", tc.enc, got, want)
+		}
+	}
+}
+
+func TestTranscodeInputErrors(t *testing.T) {
+	odd := append(encodeUTF16("a: b\n", binary.LittleEndian, true), 'x')
+	var v interface{}
+	err := UnmarshalWithOptions(odd, &v, TranscodeInput())
+	if err == nil || !strings.Contains(err.Error(), "invalid UTF-16LE input") {
+		t.Errorf("odd UTF-16 input: got error %v", err)
+	}
+
+	bad := encodeUTF32("a: b\n", binary.BigEndian, false)
+	binary.BigEndian.PutUint32(bad[4*3:], 0x110000)
+	err = UnmarshalWithOptions(bad, &v, TranscodeInput())
+	if err == nil || !strings.Contains(err.Error(), "invalid character 0x110000 at offset 12") {
+		t.Errorf("invalid UTF-32 input: got error %v", err)
+	}
+}
+
+func TestTranscodeInputStream(t *testing.T) {
+	y := encodeUTF16("a: 1\n---\na: 2\n", binary.LittleEndian, true)
+
+	var got []map[string]int
+	if err := UnmarshalDocuments(y, &got, TranscodeInput()); err != nil {
+		t.Fatalf("UnmarshalDocuments(): unexpected error: %v", err)
+	}
+	if want := []map[string]int{{"a": 1}, {"a": 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalDocuments() = %v, want %v", got, want)
+	}
+
+	split, err := SplitDocuments(y, TranscodeInput())
+	if err != nil {
+		t.Fatalf("SplitDocuments(): unexpected error: %v", err)
+	}
+	if want := [][]byte{[]byte("a: 1\n"), []byte("a: 2\n")}; !reflect.DeepEqual(split, want) {
+		t.Errorf("SplitDocuments() = %q, want %q", split, want)
+	}
+
+	docs, err := ScanDocuments(y, TranscodeInput())
+	if err != nil {
+		t.Fatalf("ScanDocuments(): unexpected error: %v", err)
+	}
+	if len(docs) != 2 || docs[1].Line != 2 || string(docs[1].Content) != "a: 2\n" {
+		t.Errorf("ScanDocuments() = %+v, want the second document on line 2", docs)
+	}
+
+	s, err := ParseStream(y, TranscodeInput())
+	if err != nil {
+		t.Fatalf("ParseStream(): unexpected error: %v", err)
+	}
+	if s.Len() != 2 || string(s.Source()) != "a: 1\n---\na: 2\n" {
+		t.Errorf("ParseStream() gave %d documents of %q, want 2", s.Len(), s.Source())
+	}
+}
@@ -0,0 +1,99 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// EnumPolicy is what UnmarshalWithOptions does with a value that is not one
+// of the values of a type registered with Enum.
+type EnumPolicy int
+
+const (
+	// RejectUnknownValues makes an unknown value an error listing the
+	// allowed values.
+	RejectUnknownValues EnumPolicy = iota
+	// DefaultUnknownValues replaces an unknown value with the first of the
+	// allowed values, and logs it if a Logger is set.
+	DefaultUnknownValues
+	// PassUnknownValues decodes an unknown value unchanged.
+	PassUnknownValues
+)
+
+// enumType holds the allowed values of a type registered with Enum.
+type enumType struct {
+	policy EnumPolicy
+	values []string
+}
+
+// Enum makes UnmarshalWithOptions check the scalars decoded into values of
+// the type of v, or pointers to it, against values, the texts they may
+// have, and handle the others according to policy. It spares config
+// loaders from validating enum fields by hand:
+//
+//	type Protocol string
+//
+//	yaml.UnmarshalWithOptions(data, &cfg, yaml.Enum(Protocol(""), yaml.RejectUnknownValues, "TCP", "UDP"))
+//
+// Null values are not checked. Enum may be given several times, for
+// different types, and applies after ParseScalars.
+func Enum(v interface{}, policy EnumPolicy, values ...string) Option {
+	t := reflect.TypeOf(v)
+	e := &enumType{policy: policy, values: append([]string(nil), values...)}
+	return func(o *options) {
+		if o.enums == nil {
+			o.enums = map[reflect.Type]*enumType{}
+		}
+		o.enums[t] = e
+	}
+}
+
+// checkEnum checks the scalar v, as decoded by go-yaml, against the values
+// of the enum type t, if it is one, returning the value to decode.
+func (c *converter) checkEnum(v interface{}, t reflect.Type) (interface{}, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	e, ok := c.opts.enums[t]
+	if !ok {
+		return v, nil
+	}
+	s, ok := scalarText(v)
+	if !ok {
+		return v, nil
+	}
+	for _, value := range e.values {
+		if s == value {
+			return v, nil
+		}
+	}
+
+	switch e.policy {
+	case DefaultUnknownValues:
+		if len(e.values) == 0 {
+			return v, nil
+		}
+		if c.opts.logger != nil {
+			c.opts.logger.Info("unknown enum value", "value", s, "type", t.String(), "default", e.values[0])
+		}
+		if t.Kind() == reflect.String {
+			return e.values[0], nil
+		}
+		var d interface{}
+		if err := yaml.Unmarshal([]byte(e.values[0]), &d); err != nil {
+			return nil, fmt.Errorf("invalid default %q for %s: %v", e.values[0], t, err)
+		}
+		return d, nil
+	case PassUnknownValues:
+		return v, nil
+	}
+	quoted := make([]string, len(e.values))
+	for i, value := range e.values {
+		quoted[i] = strconv.Quote(value)
+	}
+	return nil, fmt.Errorf("invalid value %q for %s: must be one of %s", s, t, strings.Join(quoted, ", "))
+}
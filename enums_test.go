@@ -0,0 +1,96 @@
+package yaml
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+type protocol string
+
+type level int
+
+type listener struct {
+	Protocol  protocol   `json:"protocol"`
+	Fallback  *protocol  `json:"fallback,omitempty"`
+	Protocols []protocol `json:"protocols,omitempty"`
+	Level     level      `json:"level,omitempty"`
+	Name      string     `json:"name,omitempty"`
+}
+
+func TestEnum(t *testing.T) {
+	udp := protocol("UDP")
+	tests := []struct {
+		name    string
+		input   string
+		opts    []Option
+		want    listener
+		wantErr string
+		logs    []string
+	}{
+		{
+			name:  "allowed values",
+			input: "protocol: TCP\nfallback: UDP\nprotocols: [TCP, UDP]\nname: SCTP\n",
+			opts:  []Option{Enum(protocol(""), RejectUnknownValues, "TCP", "UDP")},
+			want:  listener{Protocol: "TCP", Fallback: &udp, Protocols: []protocol{"TCP", "UDP"}, Name: "SCTP"},
+		},
+		{
+			name:    "rejected",
+			input:   "protocol: TCP\nprotocols: [UDP, SCTP]\n",
+			opts:    []Option{Enum(protocol(""), RejectUnknownValues, "TCP", "UDP")},
+			wantErr: `error converting YAML to JSON: invalid value "SCTP" for yaml.protocol: must be one of "TCP", "UDP"`,
+		},
+		{
+			name:  "defaulted",
+			input: "protocol: SCTP\nlevel: 7\n",
+			opts: []Option{
+				Enum(protocol(""), DefaultUnknownValues, "TCP", "UDP"),
+				Enum(level(0), DefaultUnknownValues, "1", "2", "3"),
+			},
+			want: listener{Protocol: "TCP", Level: 1},
+			logs: []string{
+				"info: unknown enum value [value 7 type yaml.level default 1]",
+				"info: unknown enum value [value SCTP type yaml.protocol default TCP]",
+			},
+		},
+		{
+			name:  "passed through",
+			input: "protocol: SCTP\nfallback: ~\n",
+			opts:  []Option{Enum(protocol(""), PassUnknownValues, "TCP", "UDP")},
+			want:  listener{Protocol: "SCTP"},
+		},
+		{
+			name:  "after ParseScalars",
+			input: "level: high\n",
+			opts: []Option{
+				ParseScalars(level(0), func(s string) (interface{}, error) { return map[string]int{"high": 3}[s], nil }),
+				Enum(level(0), RejectUnknownValues, "1"),
+			},
+			want: listener{Level: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logger recordingLogger
+			var got listener
+			err := UnmarshalWithOptions([]byte(tt.input), &got, append(tt.opts, WithLogger(&logger))...)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("got error %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			// Mapping keys are converted in map order.
+			sort.Strings(logger.entries)
+			if len(logger.entries) != len(tt.logs) || len(tt.logs) > 0 && !reflect.DeepEqual(logger.entries, tt.logs) {
+				t.Errorf("got logs %q, want %q", logger.entries, tt.logs)
+			}
+		})
+	}
+}
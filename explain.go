@@ -0,0 +1,241 @@
+package yaml
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// MappingStatus describes what happened to a document key during Unmarshal.
+type MappingStatus int
+
+const (
+	// Mapped keys are decoded into their field.
+	Mapped MappingStatus = iota
+	// MappedCaseInsensitive keys are decoded into a field whose name only
+	// matches the key when ignoring case.
+	MappedCaseInsensitive
+	// Unknown keys match no field; their value is dropped, or rejected by
+	// UnmarshalStrict.
+	Unknown
+	// Shadowed keys match the same field as another key of the same mapping
+	// that takes precedence, so their value is overwritten.
+	Shadowed
+	// Duplicated keys appear again later in the same mapping, so their value
+	// is overwritten by the later occurrence, or rejected by UnmarshalStrict.
+	Duplicated
+)
+
+func (s MappingStatus) String() string {
+	switch s {
+	case Mapped:
+		return "mapped"
+	case MappedCaseInsensitive:
+		return "mapped case-insensitively"
+	case Unknown:
+		return "unknown"
+	case Shadowed:
+		return "shadowed"
+	case Duplicated:
+		return "duplicated"
+	}
+	return "MappingStatus(" + strconv.Itoa(int(s)) + ")"
+}
+
+// FieldMapping explains how one key of a YAML document is decoded.
+type FieldMapping struct {
+	// Path locates the key in the document, joining mapping keys with "."
+	// and appending "[i]" for sequence entries.
+	Path string
+	// Field is the Go expression of the field the key matched, relative to
+	// the target, such as `Spec.Template.Labels["app"]`. It is empty for
+	// Unknown keys.
+	Field  string
+	Status MappingStatus
+}
+
+var (
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// Explain reports, in document order, how each key of the first document in
+// data would be decoded into target by Unmarshal: the struct field it maps
+// to, or why its value does not take. Keys below values whose type decodes
+// them itself (maps of interface{}, json.Unmarshalers, ...) are not
+// reported. target is only used for its type.
+//
+// Keys are matched to fields as UnmarshalWithOptions would with opts, such
+// as MapFieldNames and MatchFields, and through yamlalias tags.
+func Explain(data []byte, target interface{}, opts ...Option) ([]FieldMapping, error) {
+	c := &converter{fields: cachedStructFields, opts: newOptions(opts...)}
+	var d orderedDocument
+	if err := yaml.Unmarshal(data, &d); err != nil {
+		return nil, err
+	}
+	var mappings []FieldMapping
+	err := c.explainValue(d.order, d.content, reflect.TypeOf(target), "", "", &mappings)
+	return mappings, err
+}
+
+// explainValue appends the mappings of the keys in a decoded value to out.
+// order holds the value decoded with yaml.MapSlices, which keeps duplicate
+// keys, content the value decoded with maps, which resolves merge keys.
+func (c *converter) explainValue(order, content interface{}, t reflect.Type, path, goPath string, out *[]FieldMapping) error {
+	if t == nil {
+		return nil
+	}
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(jsonUnmarshalerType) || reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		items, cm := mappingItems(order, content)
+		if cm == nil {
+			return nil
+		}
+		fields := c.fields(t)
+
+		keys := make([]string, len(items))
+		for i, item := range items {
+			key, ok := keyToString(item.Key)
+			if !ok {
+				return fmt.Errorf("Unsupported map key of type: %s, key: %+#v",
+					reflect.TypeOf(item.Key), item.Key)
+			}
+			keys[i] = key
+		}
+		resolved := c.resolveKeys(t, keys)
+
+		type entry struct {
+			key string
+			r   resolvedKey
+			f   *field
+		}
+		entries := make([]entry, len(items))
+		// last holds the index of the last occurrence of each key, winner
+		// the name that ends up setting each field. Names are handed to the
+		// JSON decoder sorted, so among names matching the same field, the
+		// greatest one is decoded last and wins.
+		last := map[string]int{}
+		winner := map[*field]string{}
+		for i, key := range keys {
+			r := resolved[key]
+			f := fields.lookup([]byte(r.name))
+			entries[i] = entry{key, r, f}
+			last[key] = i
+			if w, ok := winner[f]; f != nil && r.shadowedBy == "" && (!ok || r.name > w) {
+				winner[f] = r.name
+			}
+		}
+
+		for i, e := range entries {
+			m := FieldMapping{Path: joinPath(path, e.key)}
+			switch {
+			case e.f == nil:
+				m.Status = Unknown
+			case last[e.key] != i:
+				m.Status = Duplicated
+			case e.r.shadowedBy != "" || winner[e.f] != e.r.name:
+				m.Status = Shadowed
+			case e.f.name != e.r.name:
+				m.Status = MappedCaseInsensitive
+			}
+			if e.f != nil {
+				m.Field = joinPath(goPath, fieldPath(t, e.f.index))
+			}
+			*out = append(*out, m)
+			if m.Status == Mapped || m.Status == MappedCaseInsensitive {
+				err := c.explainValue(items[i].Value, cm[items[i].Key], e.f.typ, m.Path, m.Field, out)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	case reflect.Map:
+		items, cm := mappingItems(order, content)
+		for _, item := range items {
+			key, ok := keyToString(item.Key)
+			if !ok {
+				continue
+			}
+			err := c.explainValue(item.Value, cm[item.Key], t.Elem(), joinPath(path, key),
+				goPath+"["+strconv.Quote(key)+"]", out)
+			if err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		o, _ := order.([]interface{})
+		a, _ := content.([]interface{})
+		for i := range a {
+			var oi interface{}
+			if i < len(o) {
+				oi = o[i]
+			}
+			index := "[" + strconv.Itoa(i) + "]"
+			if err := c.explainValue(oi, a[i], t.Elem(), path+index, goPath+index, out); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mappingItems returns the entries of a decoded mapping in document order,
+// duplicates included, followed by the keys brought in by merge keys. It
+// returns a nil map if content is not a mapping.
+func mappingItems(order, content interface{}) (yaml.MapSlice, map[interface{}]interface{}) {
+	cm, ok := content.(map[interface{}]interface{})
+	if !ok {
+		return nil, nil
+	}
+	items, _ := order.(yaml.MapSlice)
+	seen := make(map[interface{}]bool, len(items))
+	for _, item := range items {
+		seen[item.Key] = true
+	}
+	var merged yaml.MapSlice
+	for k, v := range cm {
+		if !seen[k] {
+			merged = append(merged, yaml.MapItem{Key: k, Value: v})
+		}
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return fmt.Sprint(merged[i].Key) < fmt.Sprint(merged[j].Key)
+	})
+	return append(items[:len(items):len(items)], merged...), cm
+}
+
+// fieldPath returns the Go field names leading to the field at index in t.
+func fieldPath(t reflect.Type, index []int) string {
+	var path string
+	for _, i := range index {
+		f := t.Field(i)
+		path = joinPath(path, f.Name)
+		t = f.Type
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	return path
+}
+
+// joinPath appends a mapping key to a dotted path.
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,104 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+type ExplainMeta struct {
+	Name   string            `json:"name"`
+	Labels map[string]string `json:"labels"`
+}
+
+type explainContainer struct {
+	Image string `json:"image"`
+}
+
+type explainSpec struct {
+	Replicas   *int               `json:"replicas"`
+	Containers []explainContainer `json:"containers"`
+}
+
+type explainTarget struct {
+	ExplainMeta
+	Kind string      `json:"kind"`
+	Spec explainSpec `json:"spec"`
+	Data map[string]*explainContainer
+	Raw  interface{} `json:"raw"`
+}
+
+func TestExplain(t *testing.T) {
+	y := []byte(`
+base: &base
+  image: nginx
+kind: A
+kind: B
+Kind: C
+name: x
+spec:
+  Replicas: 1
+  containers:
+  - image: a
+    imagePullPolicy: Always
+  - <<: *base
+data:
+  one:
+    image: b
+    extra: 1
+raw:
+  anything: 1
+`)
+	got, err := Explain(y, &explainTarget{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []FieldMapping{
+		{Path: "base", Status: Unknown},
+		{Path: "kind", Field: "Kind", Status: Duplicated},
+		{Path: "kind", Field: "Kind", Status: Mapped},
+		{Path: "Kind", Field: "Kind", Status: Shadowed},
+		{Path: "name", Field: "ExplainMeta.Name", Status: Mapped},
+		{Path: "spec", Field: "Spec", Status: Mapped},
+		{Path: "spec.Replicas", Field: "Spec.Replicas", Status: MappedCaseInsensitive},
+		{Path: "spec.containers", Field: "Spec.Containers", Status: Mapped},
+		{Path: "spec.containers[0].image", Field: "Spec.Containers[0].Image", Status: Mapped},
+		{Path: "spec.containers[0].imagePullPolicy", Status: Unknown},
+		{Path: "spec.containers[1].image", Field: "Spec.Containers[1].Image", Status: Mapped},
+		{Path: "data", Field: "Data", Status: MappedCaseInsensitive},
+		{Path: "data.one.image", Field: `Data["one"].Image`, Status: Mapped},
+		{Path: "data.one.extra", Status: Unknown},
+		{Path: "raw", Field: "Raw", Status: Mapped},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Explain() =\n%+v\nwant\n%+v", got, want)
+	}
+
+	if _, err := Explain([]byte("a: ["), &explainTarget{}); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestExplainResolvesKeys(t *testing.T) {
+	type target struct {
+		Replicas   int    `json:"replicas" yamlalias:"count, size"`
+		MaxRetries int    // untagged, renamed by MapFieldNames
+		Image      string `json:"image"`
+	}
+	y := []byte("size: 1\ncount: 2\nmax_retries: 3\nIMAGE_NAME: x\n")
+	matcher := FieldMatcherFunc(func(key, name string, _ reflect.StructField) bool {
+		return key == "IMAGE_NAME" && name == "image"
+	})
+	got, err := Explain(y, &target{}, MapFieldNames(SnakeCase), MatchFields(matcher))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []FieldMapping{
+		{Path: "size", Field: "Replicas", Status: Shadowed},
+		{Path: "count", Field: "Replicas", Status: Mapped},
+		{Path: "max_retries", Field: "MaxRetries", Status: Mapped},
+		{Path: "IMAGE_NAME", Field: "Image", Status: Mapped},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Explain() =\n%+v\nwant\n%+v", got, want)
+	}
+}
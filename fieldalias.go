@@ -0,0 +1,37 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+)
+
+// parseAliases returns the names listed in the yamlalias tag of a struct
+// field.
+func parseAliases(tag string) []string {
+	var aliases []string
+	for _, a := range strings.Split(tag, ",") {
+		if a = strings.TrimSpace(a); a != "" {
+			aliases = append(aliases, a)
+		}
+	}
+	return aliases
+}
+
+// aliasKey returns the JSON name of the field of the struct type t that key
+// is an alias of, and the position of key among the aliases of the field.
+func (c *converter) aliasKey(t reflect.Type, key string) (string, int, bool) {
+	fields := c.fields(t)
+	f, ok := fields.aliases[key]
+	if !ok {
+		return "", 0, false
+	}
+	if _, ok := fields.exact[key]; ok {
+		return "", 0, false
+	}
+	for i, a := range f.aliases {
+		if a == key {
+			return f.name, i, true
+		}
+	}
+	return f.name, 0, true
+}
@@ -0,0 +1,114 @@
+package yaml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFieldAliases(t *testing.T) {
+	type spec struct {
+		Replicas int    `json:"replicaCount" yamlalias:"replicas, count"`
+		Count    string `json:"count"`
+		Image    string `json:"image,omitempty" yamlalias:"img"`
+	}
+	tests := []struct {
+		name      string
+		input     string
+		want      spec
+		strictErr string
+	}{
+		{
+			name:  "own name",
+			input: "replicaCount: 2\n",
+			want:  spec{Replicas: 2},
+		},
+		{
+			name:  "alias",
+			input: "replicas: 3\nimg: nginx\n",
+			want:  spec{Replicas: 3, Image: "nginx"},
+		},
+		{
+			name:      "own name takes precedence",
+			input:     "replicas: 3\nreplicaCount: 4\n",
+			want:      spec{Replicas: 4},
+			strictErr: `keys "replicaCount" and "replicas" both set field "replicaCount"`,
+		},
+		{
+			name:  "alias shadowed by a field",
+			input: "count: 5\n",
+			want:  spec{Count: "5"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got spec
+			if err := Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %+v, want %+v", got, tt.want)
+			}
+			err := UnmarshalStrict([]byte(tt.input), &got)
+			if tt.strictErr == "" && err != nil {
+				t.Errorf("UnmarshalStrict(): unexpected error: %v", err)
+			}
+			if tt.strictErr != "" && (err == nil || !strings.HasSuffix(err.Error(), tt.strictErr)) {
+				t.Errorf("UnmarshalStrict() error = %v, want %q", err, tt.strictErr)
+			}
+		})
+	}
+
+	y, err := Marshal(spec{Replicas: 1, Image: "nginx"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "count: \"\"\nimage: nginx\nreplicaCount: 1\n"; string(y) != want {
+		t.Errorf("Marshal() = %q, want %q", y, want)
+	}
+}
+
+func TestFieldAliasesLogged(t *testing.T) {
+	type spec struct {
+		Replicas int `json:"replicaCount" yamlalias:"replicas"`
+	}
+	var got spec
+	l := &recordingLogger{}
+	if err := UnmarshalWithOptions([]byte("replicas: 3\n"), &got, WithLogger(l)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(l.entries) != 0 {
+		t.Errorf("non-strict decoding logged %q", l.entries)
+	}
+	// The alias is logged once, however many passes look up the fields.
+	validate := func(interface{}) error { return nil }
+	if err := UnmarshalWithOptions([]byte("replicas: 3\n"), &got, Strict(), WithLogger(l),
+		WithValidation(validate), GroupStrictErrors()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"info: deprecated key alias [key replicas field replicaCount type yaml.spec]"}
+	if len(l.entries) != 1 || l.entries[0] != want[0] {
+		t.Errorf("logged %q, want %q", l.entries, want)
+	}
+}
+
+func TestFieldAliasesConflict(t *testing.T) {
+	type spec struct {
+		Replicas int `json:"replicas" yamlalias:"count, size"`
+	}
+	// Both keys are aliases of the same field, so the outcome must not
+	// depend on map iteration order.
+	for i := 0; i < 50; i++ {
+		var got spec
+		if err := Unmarshal([]byte("size: 2\ncount: 1\n"), &got); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if got.Replicas != 1 {
+			t.Fatalf("Unmarshal() = %+v, want the first listed alias to win", got)
+		}
+	}
+	var got spec
+	err := UnmarshalStrict([]byte("size: 2\ncount: 1\n"), &got)
+	if want := `keys "count" and "size" both set field "replicas"`; err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("UnmarshalStrict() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,118 @@
+package yaml
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// parseOrder returns the weight given by the yamlorder tag of a struct
+// field, reporting false if there is none.
+func parseOrder(tag string) (int, bool) {
+	if tag == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(tag))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// hasFieldOrder reports whether a struct type reachable from t has a field
+// with a yamlorder tag.
+func (c *converter) hasFieldOrder(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Struct:
+		for _, f := range c.fields(t).list {
+			if f.ordered || c.hasFieldOrder(f.typ, seen) {
+				return true
+			}
+		}
+	case reflect.Map, reflect.Slice, reflect.Array:
+		return c.hasFieldOrder(t.Elem(), seen)
+	}
+	return false
+}
+
+// orderFields converts the objects of obj, the form decoded by go-yaml of
+// the JSON encoding of a value of type t, that encode structs with fields
+// tagged yamlorder into yaml.MapSlices: the tagged fields come first, by
+// increasing weight, and the other keys follow, sorted.
+func (c *converter) orderFields(obj interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || reflect.PtrTo(t).Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return obj
+	}
+	switch typedObj := obj.(type) {
+	case map[interface{}]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			return c.orderStruct(typedObj, t)
+		case reflect.Map:
+			for k, v := range typedObj {
+				typedObj[k] = c.orderFields(v, t.Elem())
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, v := range typedObj {
+				typedObj[i] = c.orderFields(v, t.Elem())
+			}
+		}
+	}
+	return obj
+}
+
+// orderStruct orders the keys of obj, the encoding of a struct of type t.
+func (c *converter) orderStruct(obj map[interface{}]interface{}, t reflect.Type) interface{} {
+	type entry struct {
+		key     string
+		value   interface{}
+		order   int
+		ordered bool
+	}
+	fields := c.fields(t)
+	names := c.mappedNames(t)
+	entries := make([]entry, 0, len(obj))
+	for k, v := range obj {
+		key, _ := k.(string)
+		e := entry{key: key, value: v}
+		name := key
+		if n, ok := names[key]; ok {
+			name = n
+		}
+		if f := fields.lookup([]byte(name)); f != nil {
+			e.value = c.orderFields(v, f.typ)
+			e.order, e.ordered = f.order, f.ordered
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.ordered != b.ordered {
+			return a.ordered
+		}
+		if a.order != b.order {
+			return a.order < b.order
+		}
+		return a.key < b.key
+	})
+	ms := make(yaml.MapSlice, len(entries))
+	for i, e := range entries {
+		ms[i] = yaml.MapItem{Key: e.key, Value: e.value}
+	}
+	return ms
+}
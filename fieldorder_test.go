@@ -0,0 +1,144 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+type orderedMeta struct {
+	Name      string            `json:"name" yamlorder:"1"`
+	Namespace string            `json:"namespace,omitempty" yamlorder:"2"`
+	Labels    map[string]string `json:"labels,omitempty"`
+}
+
+type orderedManifest struct {
+	Data       map[string]orderedMeta `json:"data"`
+	Items      []orderedMeta          `json:"items"`
+	Kind       string                 `json:"kind" yamlorder:"2"`
+	Metadata   orderedMeta            `json:"metadata" yamlorder:"3"`
+	APIVersion string                 `json:"apiVersion" yamlorder:"1"`
+	Zone       string                 `yamlorder:"x"`
+	MaxRetries int
+}
+
+func TestMarshalFieldOrder(t *testing.T) {
+	m := &orderedManifest{
+		Data:       map[string]orderedMeta{"b": {Name: "b", Labels: map[string]string{"z": "1", "a": "2"}}},
+		Items:      []orderedMeta{{Name: "i", Namespace: "ns"}},
+		Kind:       "ConfigMap",
+		Metadata:   orderedMeta{Name: "cm", Namespace: "default", Labels: map[string]string{"app": "x"}},
+		APIVersion: "v1",
+		Zone:       "a",
+		MaxRetries: 3,
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "default",
+			want: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+  labels:
+    app: x
+MaxRetries: 3
+Zone: a
+data:
+  b:
+    name: b
+    labels:
+      a: "2"
+      z: "1"
+items:
+- name: i
+  namespace: ns
+`,
+		},
+		{
+			name: "mapped field names",
+			opts: []Option{MapFieldNames(SnakeCase)},
+			want: `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: cm
+  namespace: default
+  labels:
+    app: x
+data:
+  b:
+    name: b
+    labels:
+      a: "2"
+      z: "1"
+items:
+- name: i
+  namespace: ns
+max_retries: 3
+zone: a
+`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalWithOptions(m, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("got\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+
+	got, err := Marshal(orderedMeta{Name: "web", Labels: map[string]string{"a": "b"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "name: web\nlabels:\n  a: b\n"; string(got) != want {
+		t.Errorf("got\n%s\nwant\n%s", got, want)
+	}
+
+	var back orderedManifest
+	y, err := Marshal(m)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := Unmarshal(y, &back); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if back.Metadata.Namespace != "default" || back.Items[0].Name != "i" || back.MaxRetries != 3 {
+		t.Errorf("round trip gave %+v", back)
+	}
+}
+
+func TestHasFieldOrder(t *testing.T) {
+	type recursive struct {
+		Next     *recursive            `json:"next"`
+		Children map[string]*recursive `json:"children"`
+	}
+	tests := []struct {
+		v    interface{}
+		want bool
+	}{
+		{orderedMeta{}, true},
+		{&[]map[string]orderedMeta{}, true},
+		{struct{ M orderedManifest }{}, true},
+		{recursive{}, false},
+		{map[string]int{}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		typ := reflect.TypeOf(tt.v)
+		// The second call is answered from the cache, if any.
+		for i := 0; i < 2; i++ {
+			if got := cachedHasFieldOrder(typ); got != tt.want {
+				t.Errorf("cachedHasFieldOrder(%v) = %v, want %v", typ, got, tt.want)
+			}
+		}
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"reflect"
 	"sort"
 	"strings"
-	"sync"
 	"unicode"
 	"unicode/utf8"
 )
@@ -76,6 +75,13 @@ type field struct {
 	typ       reflect.Type
 	omitEmpty bool
 	quoted    bool
+	encrypt   bool
+	aliases   []string // from the yamlalias tag
+	order     int      // from the yamlorder tag
+	ordered   bool     // has a yamlorder tag
+
+	deprecated  bool   // has a deprecated tag
+	deprecation string // the message of the deprecated tag
 }
 
 func fillField(f field) field {
@@ -183,13 +189,22 @@ func typeFields(t reflect.Type) []field {
 					if name == "" {
 						name = sf.Name
 					}
+					deprecation, deprecated := sf.Tag.Lookup("deprecated")
+					_, encrypt := sf.Tag.Lookup("yamlencrypt")
+					order, ordered := parseOrder(sf.Tag.Get("yamlorder"))
 					fields = append(fields, fillField(field{
-						name:      name,
-						tag:       tagged,
-						index:     index,
-						typ:       ft,
-						omitEmpty: opts.Contains("omitempty"),
-						quoted:    opts.Contains("string"),
+						name:        name,
+						tag:         tagged,
+						index:       index,
+						typ:         ft,
+						omitEmpty:   opts.Contains("omitempty"),
+						quoted:      opts.Contains("string"),
+						encrypt:     encrypt,
+						aliases:     parseAliases(sf.Tag.Get("yamlalias")),
+						order:       order,
+						ordered:     ordered,
+						deprecated:  deprecated,
+						deprecation: deprecation,
 					}))
 					if count[f.typ] > 1 {
 						// If there were multiple instances, add a second,
@@ -284,34 +299,48 @@ func dominantField(fields []field) (field, bool) {
 	return fields[0], true
 }
 
-var fieldCache struct {
-	sync.RWMutex
-	m map[reflect.Type][]field
+// structFields indexes the fields of a struct type that the JSON library
+// would recognize, for lookup by object key.
+type structFields struct {
+	list  []field
+	exact map[string]*field
+	// aliases maps the alternate names given by yamlalias tags to their
+	// fields, or is nil if there are none.
+	aliases map[string]*field
 }
 
-// cachedTypeFields is like typeFields but uses a cache to avoid repeated work.
-func cachedTypeFields(t reflect.Type) []field {
-	fieldCache.RLock()
-	f := fieldCache.m[t]
-	fieldCache.RUnlock()
-	if f != nil {
-		return f
+func newStructFields(t reflect.Type) *structFields {
+	s := &structFields{list: typeFields(t)}
+	s.exact = make(map[string]*field, len(s.list))
+	for i := range s.list {
+		f := &s.list[i]
+		s.exact[f.name] = f
+		for _, a := range f.aliases {
+			if s.aliases == nil {
+				s.aliases = map[string]*field{}
+			}
+			if _, ok := s.aliases[a]; !ok {
+				s.aliases[a] = f
+			}
+		}
 	}
+	return s
+}
 
-	// Compute fields without lock.
-	// Might duplicate effort but won't hold other computations back.
-	f = typeFields(t)
-	if f == nil {
-		f = []field{}
+// lookup returns the field the JSON library would decode key into, or nil if
+// there is none. Like the JSON library, an exact match is preferred over a
+// case-insensitive one.
+func (s *structFields) lookup(key []byte) *field {
+	if f, ok := s.exact[string(key)]; ok {
+		return f
 	}
-
-	fieldCache.Lock()
-	if fieldCache.m == nil {
-		fieldCache.m = map[reflect.Type][]field{}
+	for i := range s.list {
+		f := &s.list[i]
+		if f.equalFold(f.nameBytes, key) {
+			return f
+		}
 	}
-	fieldCache.m[t] = f
-	fieldCache.Unlock()
-	return f
+	return nil
 }
 
 func isValidTag(s string) bool {
@@ -0,0 +1,66 @@
+// Copyright 2013 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// +build !yaml_nocache
+
+package yaml
+
+import (
+	"reflect"
+	"sync"
+)
+
+var fieldCache struct {
+	sync.RWMutex
+	m map[reflect.Type]*structFields
+}
+
+// cachedStructFields is like newStructFields but uses a cache to avoid
+// repeated work.
+func cachedStructFields(t reflect.Type) *structFields {
+	fieldCache.RLock()
+	f := fieldCache.m[t]
+	fieldCache.RUnlock()
+	if f != nil {
+		return f
+	}
+
+	// Compute fields without lock.
+	// Might duplicate effort but won't hold other computations back.
+	f = newStructFields(t)
+
+	fieldCache.Lock()
+	if fieldCache.m == nil {
+		fieldCache.m = map[reflect.Type]*structFields{}
+	}
+	fieldCache.m[t] = f
+	fieldCache.Unlock()
+	return f
+}
+
+var fieldOrderCache struct {
+	sync.RWMutex
+	m map[reflect.Type]bool
+}
+
+// cachedHasFieldOrder is like hasFieldOrder but uses a cache to avoid
+// walking the types reachable from t on every Marshal.
+func cachedHasFieldOrder(t reflect.Type) bool {
+	fieldOrderCache.RLock()
+	ordered, ok := fieldOrderCache.m[t]
+	fieldOrderCache.RUnlock()
+	if ok {
+		return ordered
+	}
+
+	ordered = defaultConverter.hasFieldOrder(t, map[reflect.Type]bool{})
+
+	fieldOrderCache.Lock()
+	if fieldOrderCache.m == nil {
+		fieldOrderCache.m = map[reflect.Type]bool{}
+	}
+	fieldOrderCache.m[t] = ordered
+	fieldOrderCache.Unlock()
+	return ordered
+}
@@ -0,0 +1,22 @@
+// Building with the yaml_nocache tag makes this package keep no cache of the
+// fields of struct types, for constrained environments such as WebAssembly
+// runtimes and policy engines that embed it to evaluate a few manifests,
+// where the memory of a process-wide cache outweighs the reflection it
+// saves. encoding/json, which does the final decode, keeps its own cache.
+
+// +build yaml_nocache
+
+package yaml
+
+import "reflect"
+
+// cachedStructFields returns the fields of t, computing them on every call.
+func cachedStructFields(t reflect.Type) *structFields {
+	return newStructFields(t)
+}
+
+// cachedHasFieldOrder returns whether t has ordered fields, walking the types
+// reachable from it on every call.
+func cachedHasFieldOrder(t reflect.Type) bool {
+	return defaultConverter.hasFieldOrder(t, map[reflect.Type]bool{})
+}
@@ -0,0 +1,26 @@
+// +build yaml_nocache
+
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNoFieldCache(t *testing.T) {
+	type T struct {
+		A string `json:"a"`
+	}
+	typ := reflect.TypeOf(T{})
+	if cachedStructFields(typ) == cachedStructFields(typ) {
+		t.Error("fields of struct types are cached")
+	}
+
+	var v T
+	if err := Unmarshal([]byte("a: b\n"), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v.A != "b" {
+		t.Errorf("got %q, want %q", v.A, "b")
+	}
+}
@@ -0,0 +1,132 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// FloatFormat is a policy for writing floating-point numbers. See
+// FormatFloats.
+type FloatFormat int
+
+const (
+	// JSONFloatFormat writes floats the way encoding/json does: with the
+	// fewest digits that represent the value exactly, in exponent form only
+	// for magnitudes below 1e-6 or from 1e21 on.
+	JSONFloatFormat FloatFormat = iota + 1
+	// DecimalFloatFormat writes floats with the fewest digits that represent
+	// the value exactly, never in exponent form.
+	DecimalFloatFormat
+)
+
+// FormatFloats makes MarshalWithOptions, JSONToYAMLWithOptions and
+// YAMLToJSONWithOptions write floating-point numbers according to f, so that
+// a value is written the same way by the YAML and JSON layers. Otherwise
+// go-yaml switches to exponent form from 1e+06 on while encoding/json does
+// so from 1e+21 on. Integers, infinities and NaN are unaffected.
+func FormatFloats(f FloatFormat) Option {
+	return func(o *options) {
+		o.floatFormat = f
+	}
+}
+
+// IntegerDigits makes MarshalWithOptions and JSONToYAMLWithOptions write
+// floats with an integer value, and integers too large for an int64 or
+// uint64, in full digits rather than in exponent form, so that a memory limit
+// of 1e9 is written 1000000000 and not 1e+09. Integers that go-yaml can
+// only hold as a float64 keep the significant digits it would write, padded
+// with zeros. Other floats are left to FormatFloats.
+func IntegerDigits() Option {
+	return func(o *options) {
+		o.integerDigits = true
+	}
+}
+
+// formatFloat formats f according to the policy format.
+func formatFloat(f float64, format FloatFormat) string {
+	if format == DecimalFloatFormat {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	// Like encoding/json, but without the special case for float32.
+	abs := math.Abs(f)
+	if abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		s := strconv.FormatFloat(f, 'e', -1, 64)
+		// Clean up e-09 to e-9.
+		if n := len(s); n >= 4 && s[n-4] == 'e' && s[n-3] == '-' && s[n-2] == '0' {
+			s = s[:n-2] + s[n-1:]
+		}
+		return s
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+// yamlFloat matches the plain scalars go-yaml resolves to floats, other than
+// infinities and NaN.
+var yamlFloat = regexp.MustCompile(`^[-+]?(\.[0-9]+|[0-9]+(\.[0-9]*)?)([eE][-+]?[0-9]+)?$`)
+
+// rewriteFloats replaces the plain scalars of y that are floats, and that
+// are not mapping keys, by the result of format, where it returns true.
+func rewriteFloats(y []byte, format func(f float64) (string, bool)) []byte {
+	var out bytes.Buffer
+	last := 0
+	for _, t := range scanTokens(y) {
+		if t.kind != plainToken || t.multiline || (t.end < len(y) && y[t.end] == ':') {
+			continue
+		}
+		s := string(y[t.start:t.end])
+		if !yamlFloat.MatchString(s) || !strings.ContainsAny(s, ".eE") {
+			continue
+		}
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			continue
+		}
+		if r, ok := format(f); ok && r != s {
+			out.Write(y[last:t.start])
+			out.WriteString(r)
+			last = t.end
+		}
+	}
+	if last == 0 {
+		return y
+	}
+	out.Write(y[last:])
+	return out.Bytes()
+}
+
+// formatYAMLFloat returns how the options o write the float f in YAML, and
+// false if they leave it as go-yaml writes it.
+func (o *options) formatYAMLFloat(f float64) (string, bool) {
+	if o.integerDigits && f == math.Trunc(f) {
+		return strconv.FormatFloat(f, 'f', -1, 64), true
+	}
+	if o.floatFormat != 0 {
+		return formatFloat(f, o.floatFormat), true
+	}
+	return "", false
+}
+
+// formatJSONFloats replaces the float64s in the JSON-compatible object obj
+// by json.Numbers formatted according to format, so that encoding/json
+// writes them as such.
+func formatJSONFloats(obj interface{}, format FloatFormat) interface{} {
+	switch v := obj.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			v[k] = formatJSONFloats(e, format)
+		}
+	case []interface{}:
+		for i, e := range v {
+			v[i] = formatJSONFloats(e, format)
+		}
+	case float64:
+		if !math.IsInf(v, 0) && !math.IsNaN(v) {
+			return json.Number(formatFloat(v, format))
+		}
+	}
+	return obj
+}
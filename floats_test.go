@@ -0,0 +1,121 @@
+package yaml
+
+import (
+	"testing"
+)
+
+func TestFormatFloat(t *testing.T) {
+	tests := []struct {
+		f             float64
+		json, decimal string
+	}{
+		{3, "3", "3"},
+		{1.5, "1.5", "1.5"},
+		{1e9, "1000000000", "1000000000"},
+		{1e36, "1e+36", "1000000000000000000000000000000000000"},
+		{-2.5e-7, "-2.5e-7", "-0.00000025"},
+		{0, "0", "0"},
+	}
+	for _, tt := range tests {
+		if got := formatFloat(tt.f, JSONFloatFormat); got != tt.json {
+			t.Errorf("formatFloat(%v, JSONFloatFormat) = %q, want %q", tt.f, got, tt.json)
+		}
+		if got := formatFloat(tt.f, DecimalFloatFormat); got != tt.decimal {
+			t.Errorf("formatFloat(%v, DecimalFloatFormat) = %q, want %q", tt.f, got, tt.decimal)
+		}
+	}
+}
+
+func TestFormatFloats(t *testing.T) {
+	j := []byte(`{"big":1e36,"mem":1.5e9,"small":2.5e-7,"half":0.5,"int":12,"s":"1e+09"}`)
+	y := "big: 1e+36\nhalf: 0.5\nint: 12\nmem: 1.5e+09\ns: \"1e+09\"\nsmall: 2.5e-07\n"
+
+	tests := []struct {
+		name     string
+		format   FloatFormat
+		wantYAML string
+		wantJSON string
+	}{
+		{
+			name:     "default",
+			wantYAML: "big: 1e+36\nhalf: 0.5\nint: 12\nmem: 1.5e+09\ns: \"1e+09\"\nsmall: 2.5e-07\n",
+			wantJSON: `{"big":1e+36,"half":0.5,"int":12,"mem":1500000000,"s":"1e+09","small":2.5e-7}`,
+		},
+		{
+			name:     "JSON",
+			format:   JSONFloatFormat,
+			wantYAML: "big: 1e+36\nhalf: 0.5\nint: 12\nmem: 1500000000\ns: \"1e+09\"\nsmall: 2.5e-7\n",
+			wantJSON: `{"big":1e+36,"half":0.5,"int":12,"mem":1500000000,"s":"1e+09","small":2.5e-7}`,
+		},
+		{
+			name:     "decimal",
+			format:   DecimalFloatFormat,
+			wantYAML: "big: 1000000000000000000000000000000000000\nhalf: 0.5\nint: 12\nmem: 1500000000\ns: \"1e+09\"\nsmall: 0.00000025\n",
+			wantJSON: `{"big":1000000000000000000000000000000000000,"half":0.5,"int":12,"mem":1500000000,"s":"1e+09","small":0.00000025}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.format != 0 {
+				opts = append(opts, FormatFloats(tt.format))
+			}
+			gotYAML, err := JSONToYAMLWithOptions(j, opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(gotYAML) != tt.wantYAML {
+				t.Errorf("JSONToYAMLWithOptions() = %q, want %q", gotYAML, tt.wantYAML)
+			}
+			gotJSON, err := YAMLToJSONWithOptions([]byte(y), opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(gotJSON) != tt.wantJSON {
+				t.Errorf("YAMLToJSONWithOptions() = %s, want %s", gotJSON, tt.wantJSON)
+			}
+		})
+	}
+}
+
+func TestIntegerDigits(t *testing.T) {
+	j := []byte(`{"mem":1e9,"big":123456789012345678901234,"huge":1e22,"half":2.5e-7,"neg":-3.0e+12}`)
+
+	got, err := JSONToYAMLWithOptions(j)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "big: 1.2345678901234569e+23\nhalf: 2.5e-07\nhuge: 1e+22\nmem: 1e+09\nneg: -3e+12\n"
+	if string(got) != want {
+		t.Errorf("JSONToYAMLWithOptions() = %q, want %q", got, want)
+	}
+
+	got, err = JSONToYAMLWithOptions(j, IntegerDigits())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = "big: 123456789012345690000000\nhalf: 2.5e-07\nhuge: 10000000000000000000000\nmem: 1000000000\nneg: -3000000000000\n"
+	if string(got) != want {
+		t.Errorf("JSONToYAMLWithOptions(IntegerDigits()) = %q, want %q", got, want)
+	}
+
+	got, err = JSONToYAMLWithOptions(j, IntegerDigits(), FormatFloats(JSONFloatFormat))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = "big: 123456789012345690000000\nhalf: 2.5e-7\nhuge: 10000000000000000000000\nmem: 1000000000\nneg: -3000000000000\n"
+	if string(got) != want {
+		t.Errorf("JSONToYAMLWithOptions(IntegerDigits(), FormatFloats()) = %q, want %q", got, want)
+	}
+
+	type limits struct {
+		Memory float64 `json:"memory"`
+	}
+	got, err = MarshalWithOptions(limits{Memory: 4e9}, IntegerDigits())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := "memory: 4000000000\n"; string(got) != want {
+		t.Errorf("MarshalWithOptions() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,61 @@
+// Package yaml provides the API of github.com/ghodss/yaml on top of
+// sigs.k8s.io/yaml, so that code written against it can switch by changing
+// its import path alone:
+//
+//	import "sigs.k8s.io/yaml/ghodss"
+//
+// The functions keep their historical signatures, including the JSONOpt
+// arguments of the decoding functions, and behave like the functions of
+// sigs.k8s.io/yaml they are named after. New code should use
+// sigs.k8s.io/yaml directly.
+package yaml
+
+import (
+	"encoding/json"
+
+	"sigs.k8s.io/yaml"
+)
+
+// JSONOpt is a decoding option for decoding from JSON format.
+type JSONOpt = yaml.JSONOpt
+
+// Marshal marshals the object into JSON then converts JSON to YAML and
+// returns the YAML.
+func Marshal(o interface{}) ([]byte, error) {
+	return yaml.MarshalWithOptions(o)
+}
+
+// Unmarshal converts YAML to JSON then uses JSON to unmarshal into an
+// object, optionally configuring the behavior of the JSON unmarshal.
+func Unmarshal(y []byte, o interface{}, opts ...JSONOpt) error {
+	return yaml.UnmarshalWithOptions(y, o, yaml.JSONOpts(opts...))
+}
+
+// UnmarshalStrict strictly converts YAML to JSON then uses JSON to unmarshal
+// into an object, optionally configuring the behavior of the JSON unmarshal.
+func UnmarshalStrict(y []byte, o interface{}, opts ...JSONOpt) error {
+	return yaml.UnmarshalWithOptions(y, o, yaml.Strict(), yaml.JSONOpts(opts...))
+}
+
+// JSONToYAML converts JSON to YAML.
+func JSONToYAML(j []byte) ([]byte, error) {
+	return yaml.JSONToYAMLWithOptions(j)
+}
+
+// YAMLToJSON converts YAML to JSON. Since JSON is a subset of YAML, passing
+// JSON through this method should be a no-op.
+func YAMLToJSON(y []byte) ([]byte, error) {
+	return yaml.YAMLToJSONWithOptions(y)
+}
+
+// YAMLToJSONStrict is like YAMLToJSON but enables strict YAML decoding,
+// returning an error on any duplicate field names.
+func YAMLToJSONStrict(y []byte) ([]byte, error) {
+	return yaml.YAMLToJSONWithOptions(y, yaml.Strict())
+}
+
+// DisallowUnknownFields configures the JSON decoder to error out if unknown
+// fields come along, instead of dropping them by default.
+func DisallowUnknownFields(d *json.Decoder) *json.Decoder {
+	return yaml.DisallowUnknownFields(d)
+}
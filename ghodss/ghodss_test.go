@@ -0,0 +1,64 @@
+package yaml
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	upstream "sigs.k8s.io/yaml"
+)
+
+type config struct {
+	Name  string      `json:"name"`
+	Count json.Number `json:"count"`
+}
+
+func TestUnmarshal(t *testing.T) {
+	useNumber := func(d *json.Decoder) *json.Decoder {
+		d.UseNumber()
+		return d
+	}
+	var v map[string]interface{}
+	if err := Unmarshal([]byte("a: 1\n"), &v, useNumber); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]interface{}{"a": json.Number("1")}; !reflect.DeepEqual(v, want) {
+		t.Errorf("got %#v, want %#v", v, want)
+	}
+
+	var c config
+	err := Unmarshal([]byte("name: web\ncount: 2\nextra: x\n"), &c, DisallowUnknownFields)
+	if err == nil || !strings.Contains(err.Error(), `unknown field "extra"`) {
+		t.Errorf("got error %v, want unknown field", err)
+	}
+}
+
+func TestUnmarshalStrict(t *testing.T) {
+	for _, y := range []string{"name: a\nname: b\n", "name: a\nextra: x\n"} {
+		var got, want config
+		err := UnmarshalStrict([]byte(y), &got)
+		wantErr := upstream.UnmarshalStrict([]byte(y), &want)
+		if err == nil || wantErr == nil || err.Error() != wantErr.Error() {
+			t.Errorf("UnmarshalStrict(%q) = %v, want %v", y, err, wantErr)
+		}
+	}
+}
+
+func TestConversions(t *testing.T) {
+	y, err := Marshal(config{Name: "web", Count: "2"})
+	if err != nil || string(y) != "count: 2\nname: web\n" {
+		t.Errorf("Marshal() = %q, %v", y, err)
+	}
+	y, err = JSONToYAML([]byte(`{"b":1,"a":[true]}`))
+	if err != nil || string(y) != "a:\n- true\nb: 1\n" {
+		t.Errorf("JSONToYAML() = %q, %v", y, err)
+	}
+	j, err := YAMLToJSON([]byte("b: 1\na: [true]\n"))
+	if err != nil || string(j) != `{"a":[true],"b":1}` {
+		t.Errorf("YAMLToJSON() = %q, %v", j, err)
+	}
+	if _, err := YAMLToJSONStrict([]byte("a: 1\na: 2\n")); err == nil {
+		t.Error("YAMLToJSONStrict() accepted a duplicate key")
+	}
+}
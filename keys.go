@@ -0,0 +1,242 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// MapFieldNames makes MarshalWithOptions and UnmarshalWithOptions use
+// fn(name) as the document key of struct fields without a name in their
+// json tag, where name is the Go field name. It lets documents written in
+// another naming style, such as snake_case with the SnakeCase function, map
+// onto Go types without annotating every field. Fields named by their json
+// tag are unaffected. The Go name is still accepted when decoding; if a
+// mapping holds both names of a field, the Go name wins, and strict decoding
+// rejects the mapping.
+func MapFieldNames(fn func(name string) string) Option {
+	return func(o *options) {
+		o.fieldNameMapper = fn
+	}
+}
+
+// SnakeCase converts a Go identifier to snake_case, treating runs of
+// capitals as a single word: "MaxRetries" becomes "max_retries" and
+// "HTTPServer" becomes "http_server".
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			// A capital starts a word after a lowercase letter or digit, or
+			// when it is followed by a lowercase letter after other capitals.
+			if i > 0 && (!unicode.IsUpper(runes[i-1]) && runes[i-1] != '_' ||
+				i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// mappedNames returns, for the struct type t, the Go names of its untagged
+// fields keyed by their mapped names, or nil without MapFieldNames.
+func (c *converter) mappedNames(t reflect.Type) map[string]string {
+	if c.opts == nil || c.opts.fieldNameMapper == nil {
+		return nil
+	}
+	if names, ok := c.opts.mappedNames[t]; ok {
+		return names
+	}
+	names := map[string]string{}
+	for _, f := range c.fields(t).list {
+		if !f.tag {
+			names[c.opts.fieldNameMapper(f.name)] = f.name
+		}
+	}
+	if c.opts.mappedNames == nil {
+		c.opts.mappedNames = map[reflect.Type]map[string]string{}
+	}
+	c.opts.mappedNames[t] = names
+	return names
+}
+
+// keySource tells how a document key was resolved to the name of a field.
+// Lower sources take precedence when several keys set the same field.
+type keySource int
+
+const (
+	ownName keySource = iota
+	mappedName
+	aliasName
+	matchedName
+)
+
+// resolvedKey is a document key resolved to the struct field it sets.
+type resolvedKey struct {
+	// name is the JSON name of the field, or the key itself.
+	name   string
+	source keySource
+	// rank orders the keys of the same source, such as aliases by their
+	// position in the yamlalias tag.
+	rank int
+	// shadowedBy is the key of the same mapping that sets the field
+	// instead, if any.
+	shadowedBy string
+}
+
+// resolveKey resolves the document key of a mapping decoded into the struct
+// type t with MapFieldNames, a yamlalias tag or MatchFields, in that order.
+func (c *converter) resolveKey(t reflect.Type, key string) resolvedKey {
+	if name, ok := c.mappedNames(t)[key]; ok && name != key {
+		return resolvedKey{name: name, source: mappedName}
+	}
+	if name, i, ok := c.aliasKey(t, key); ok {
+		return resolvedKey{name: name, source: aliasName, rank: i}
+	}
+	if c.opts != nil && c.opts.fieldMatcher != nil {
+		if name := c.matchField(t, key); name != key {
+			return resolvedKey{name: name, source: matchedName}
+		}
+	}
+	return resolvedKey{name: key}
+}
+
+// mapKey returns the JSON name of the field of the struct type t that the
+// document key maps to, or key itself. See resolveKey.
+func (c *converter) mapKey(t reflect.Type, key string) string {
+	return c.resolveKey(t, key).name
+}
+
+// resolveKeys resolves the keys of a mapping decoded into the struct type t.
+// When several keys resolve to the same name, the one with the lowest
+// source, then rank, then key wins, so that the field's own key comes
+// first; the others are shadowed by it.
+func (c *converter) resolveKeys(t reflect.Type, keys []string) map[string]resolvedKey {
+	resolved := make(map[string]resolvedKey, len(keys))
+	byName := map[string][]string{}
+	for _, k := range keys {
+		if _, ok := resolved[k]; ok {
+			// A duplicate key; the decoder keeps its last value.
+			continue
+		}
+		r := c.resolveKey(t, k)
+		resolved[k] = r
+		byName[r.name] = append(byName[r.name], k)
+	}
+	for _, ks := range byName {
+		if len(ks) < 2 {
+			continue
+		}
+		sort.Slice(ks, func(i, j int) bool {
+			a, b := resolved[ks[i]], resolved[ks[j]]
+			if a.source != b.source {
+				return a.source < b.source
+			}
+			if a.rank != b.rank {
+				return a.rank < b.rank
+			}
+			return ks[i] < ks[j]
+		})
+		for _, k := range ks[1:] {
+			r := resolved[k]
+			r.shadowedBy = ks[0]
+			resolved[k] = r
+		}
+	}
+	return resolved
+}
+
+// resolveStructKeys resolves the keys of the mapping m decoded into the
+// struct type t, logging the aliases used and, when decoding strictly,
+// rejecting keys that set the same field.
+func (c *converter) resolveStructKeys(t reflect.Type, m map[interface{}]interface{}) (map[string]resolvedKey, error) {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		if s, ok := keyToString(k); ok {
+			keys = append(keys, s)
+		}
+	}
+	sort.Strings(keys)
+	resolved := c.resolveKeys(t, keys)
+	for _, k := range keys {
+		r := resolved[k]
+		if r.source == aliasName && c.strict && c.opts != nil && c.opts.logger != nil {
+			c.opts.logger.Info("deprecated key alias", "key", k, "field", r.name, "type", t.String())
+		}
+		if r.shadowedBy != "" && c.strict {
+			msg := keyConflict(k, r)
+			c.strictErrors([]string{msg})
+			return nil, errors.New(msg)
+		}
+	}
+	return resolved, nil
+}
+
+// keyConflict returns the message reporting that key sets the same field
+// as another key of the same mapping.
+func keyConflict(key string, r resolvedKey) string {
+	return fmt.Sprintf("keys %q and %q both set field %q", r.shadowedBy, key, r.name)
+}
+
+// mapFieldNamesJSON renames the keys of the untagged struct fields in the
+// JSON document j, the encoding of a value of type t, with the mapper set by
+// MapFieldNames.
+func (c *converter) mapFieldNamesJSON(j []byte, t reflect.Type) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(j))
+	d.UseNumber()
+	var obj interface{}
+	if err := d.Decode(&obj); err != nil {
+		return nil, err
+	}
+	return json.Marshal(c.mapFieldNames(obj, t))
+}
+
+// mapFieldNames renames the keys of the untagged struct fields in obj, the
+// JSON-compatible form of a value of type t.
+func (c *converter) mapFieldNames(obj interface{}, t reflect.Type) interface{} {
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || reflect.PtrTo(t).Implements(jsonMarshalerType) || reflect.PtrTo(t).Implements(textMarshalerType) {
+		return obj
+	}
+	switch typedObj := obj.(type) {
+	case map[string]interface{}:
+		switch t.Kind() {
+		case reflect.Struct:
+			fields := c.fields(t)
+			out := make(map[string]interface{}, len(typedObj))
+			for k, v := range typedObj {
+				f := fields.lookup([]byte(k))
+				if f == nil {
+					out[k] = v
+					continue
+				}
+				if !f.tag {
+					k = c.opts.fieldNameMapper(f.name)
+				}
+				out[k] = c.mapFieldNames(v, f.typ)
+			}
+			return out
+		case reflect.Map:
+			for k, v := range typedObj {
+				typedObj[k] = c.mapFieldNames(v, t.Elem())
+			}
+		}
+	case []interface{}:
+		if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+			for i, v := range typedObj {
+				typedObj[i] = c.mapFieldNames(v, t.Elem())
+			}
+		}
+	}
+	return obj
+}
@@ -0,0 +1,108 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSnakeCase(t *testing.T) {
+	tests := map[string]string{
+		"Name":           "name",
+		"MaxRetries":     "max_retries",
+		"HTTPServer":     "http_server",
+		"ServerURL":      "server_url",
+		"IPv6Address":    "i_pv6_address",
+		"Port8080Active": "port8080_active",
+		"Already_Snake":  "already_snake",
+	}
+	for in, want := range tests {
+		if got := SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+type KeysInner struct {
+	RetryCount int
+	Tagged     string `json:"taggedName"`
+}
+
+type KeysConfig struct {
+	ServerURL  string
+	MaxRetries *int
+	Inner      KeysInner
+	Items      []KeysInner
+	ByName     map[string]KeysInner
+	Labels     map[string]string `json:"labels"`
+}
+
+func TestMapFieldNames(t *testing.T) {
+	three := 3
+	v := KeysConfig{
+		ServerURL:  "http://x",
+		MaxRetries: &three,
+		Inner:      KeysInner{RetryCount: 1, Tagged: "t"},
+		Items:      []KeysInner{{RetryCount: 2}},
+		ByName:     map[string]KeysInner{"MixedCase": {RetryCount: 4}},
+		Labels:     map[string]string{"KeepMe": "x"},
+	}
+	want := `by_name:
+  MixedCase:
+    retry_count: 4
+    taggedName: ""
+inner:
+  retry_count: 1
+  taggedName: t
+items:
+- retry_count: 2
+  taggedName: ""
+labels:
+  KeepMe: x
+max_retries: 3
+server_url: http://x
+`
+	y, err := MarshalWithOptions(v, MapFieldNames(SnakeCase))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(y) != want {
+		t.Errorf("MarshalWithOptions() = %q, want %q", y, want)
+	}
+
+	var out KeysConfig
+	if err := UnmarshalWithOptions(y, &out, MapFieldNames(SnakeCase), Strict()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(out, v) {
+		t.Errorf("UnmarshalWithOptions() = %#v, want %#v", out, v)
+	}
+
+	// Go names keep working.
+	out = KeysConfig{}
+	if err := UnmarshalWithOptions([]byte("ServerURL: a\n"), &out, MapFieldNames(SnakeCase)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if out.ServerURL != "a" {
+		t.Errorf("ServerURL = %q, want %q", out.ServerURL, "a")
+	}
+}
+
+func TestMapFieldNamesCollision(t *testing.T) {
+	y := []byte("server_url: a\nServerURL: b\n")
+	// The outcome must not depend on map iteration order.
+	for i := 0; i < 50; i++ {
+		var out KeysConfig
+		if err := UnmarshalWithOptions(y, &out, MapFieldNames(SnakeCase)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if out.ServerURL != "b" {
+			t.Fatalf("ServerURL = %q, want the Go name to win", out.ServerURL)
+		}
+	}
+	var out KeysConfig
+	err := UnmarshalWithOptions(y, &out, MapFieldNames(SnakeCase), Strict())
+	if want := `keys "ServerURL" and "server_url" both set field "ServerURL"`; err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("strict UnmarshalWithOptions() error = %v, want %q", err, want)
+	}
+}
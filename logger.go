@@ -0,0 +1,50 @@
+package yaml
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+)
+
+// Logger receives the problems found while decoding. It is the subset of
+// the methods of github.com/go-logr/logr.Logger used by this package, so a
+// logr.Logger can be passed as is.
+type Logger interface {
+	Info(msg string, keysAndValues ...interface{})
+	Error(err error, msg string, keysAndValues ...interface{})
+}
+
+// WithLogger makes UnmarshalWithOptions report to l each strict error as it
+// is found, with the line it was found on when known, and the warnings of
+// TolerateTabIndentation. The errors are still returned as usual.
+//
+// It also makes UnmarshalWithOptions warn about the struct fields tagged
+// deprecated that the document sets, even to null, giving configuration
+// owners a way to sunset options:
+//
+//	Replicas int `json:"replicas" deprecated:"use replicaCount instead"`
+//
+// Each such field is logged with l.Info("deprecated field", "field", path,
+// "line", line, "reason", message), where path joins mapping keys with "."
+// and appends "[i]" for sequence entries. The line is left out when unknown
+// and the reason when the tag is empty. The warnings do not make decoding
+// fail.
+func WithLogger(l Logger) Option {
+	return func(o *options) {
+		o.logger = l
+	}
+}
+
+// strictErrorLine matches the line number go-yaml puts in front of its
+// decoding errors.
+var strictErrorLine = regexp.MustCompile(`^line (\d+): `)
+
+// logStrictError reports a strict decoding error to l.
+func logStrictError(l Logger, msg string) {
+	if m := strictErrorLine.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		l.Error(errors.New(msg[len(m[0]):]), "strict decoding error", "line", line)
+		return
+	}
+	l.Error(errors.New(msg), "strict decoding error")
+}
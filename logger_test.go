@@ -0,0 +1,93 @@
+package yaml
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// recordingLogger records the messages it receives as strings.
+type recordingLogger struct {
+	entries []string
+}
+
+func (l *recordingLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, fmt.Sprintf("info: %s %v", msg, keysAndValues))
+}
+
+func (l *recordingLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.entries = append(l.entries, fmt.Sprintf("error: %s: %v %v", msg, err, keysAndValues))
+}
+
+func TestWithLogger(t *testing.T) {
+	var v struct {
+		A int `json:"a"`
+	}
+
+	l := &recordingLogger{}
+	y := []byte("a: 1\nb:\n\tc: 2\na: 3\nb: 4\n")
+	if err := UnmarshalWithOptions(y, &v, WithLogger(l), Strict(), TolerateTabIndentation(2, nil)); err == nil {
+		t.Fatal("expected strict error")
+	}
+	want := []string{
+		"info: expanded tab indentation [lines [3]]",
+		`error: strict decoding error: key "a" already set in map [line 4]`,
+		`error: strict decoding error: key "b" already set in map [line 5]`,
+	}
+	if !reflect.DeepEqual(l.entries, want) {
+		t.Errorf("logged %q, want %q", l.entries, want)
+	}
+
+	l = &recordingLogger{}
+	if err := UnmarshalWithOptions([]byte("a: 1\nb: 2\n"), &v, WithLogger(l), Strict()); err == nil {
+		t.Fatal("expected strict error")
+	}
+	want = []string{`error: strict decoding error: unknown field "b" []`}
+	if !reflect.DeepEqual(l.entries, want) {
+		t.Errorf("logged %q, want %q", l.entries, want)
+	}
+
+	// Every unknown field is reported, but not an error of a field's own
+	// decoding that merely reads like one.
+	var w struct {
+		A struct {
+			B int `json:"b"`
+		} `json:"a"`
+		R rejectingUnmarshaler `json:"r"`
+	}
+	l = &recordingLogger{}
+	if err := UnmarshalWithOptions([]byte("a: {b: 1, z: 2}\nx: 1\n"), &w, WithLogger(l), Strict()); err == nil {
+		t.Fatal("expected strict error")
+	}
+	want = []string{
+		`error: strict decoding error: unknown field "x" []`,
+		`error: strict decoding error: unknown field "z" []`,
+	}
+	if !reflect.DeepEqual(l.entries, want) {
+		t.Errorf("logged %q, want %q", l.entries, want)
+	}
+	l = &recordingLogger{}
+	if err := UnmarshalWithOptions([]byte("r: 1\n"), &w, WithLogger(l), Strict()); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(l.entries) != 0 {
+		t.Errorf("logged %q, want nothing", l.entries)
+	}
+
+	l = &recordingLogger{}
+	if err := UnmarshalWithOptions([]byte("a: 1\nb: 2\n"), &v, WithLogger(l)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(l.entries) != 0 {
+		t.Errorf("logged %q, want nothing", l.entries)
+	}
+}
+
+// rejectingUnmarshaler fails to decode with an error worded like the JSON
+// decoder's for unknown fields.
+type rejectingUnmarshaler struct{}
+
+func (rejectingUnmarshaler) UnmarshalJSON([]byte) error {
+	return errors.New(`json: unknown field "r"`)
+}
@@ -0,0 +1,113 @@
+package yaml
+
+import (
+	"fmt"
+	"strconv"
+
+	"gopkg.in/yaml.v2"
+)
+
+// MappingEntry is an entry of a YAML mapping.
+type MappingEntry struct {
+	// Key and Value are the decoded key and value. Mappings within Value
+	// are yaml.MapSlices, which keep their keys in document order.
+	Key   interface{}
+	Value interface{}
+	// Path locates the entry in the document, joining mapping keys with "."
+	// and appending "[i]" for sequence entries.
+	Path string
+	// Line and Column locate the key in the document, 1-based. They are 0
+	// for entries of flow mappings ("{...}") and entries brought in by merge
+	// keys.
+	Line   int
+	Column int
+}
+
+// MappingEntries returns the entries of the mapping at path in the first
+// YAML document of doc, in document order rather than in the random order
+// of Go maps, so that tools applying "first match wins" rules behave
+// deterministically. An empty path denotes the document's root. Keys
+// brought in by merge keys ("<<") follow the mapping's own keys, and a key
+// that appears twice keeps its first position with its last value.
+//
+// As path is split at "." and "[", it cannot name a key containing them,
+// although such keys are reported among the entries of their mapping.
+// An error is returned if there is no mapping at path.
+func MappingEntries(doc []byte, path string) ([]MappingEntry, error) {
+	obj, err := yamlUnmarshalOrdered(doc, yaml.Unmarshal)
+	if err != nil {
+		return nil, err
+	}
+	for _, segment := range splitPath(path) {
+		obj, err = childValue(obj, segment)
+		if err != nil {
+			return nil, fmt.Errorf("yaml: %s: %v", path, err)
+		}
+	}
+	ms, ok := obj.(yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("yaml: %s: not a mapping", path)
+	}
+
+	nodes, err := blockNodes(doc, scanTokens(doc))
+	if err != nil {
+		return nil, err
+	}
+	// Follow the key tokens down to the mapping: parent ends up as the
+	// index of the node holding it, -1 for the root, or len(nodes) if it is
+	// not within block collections.
+	parent := -1
+	for _, segment := range splitPath(path) {
+		next := len(nodes)
+		for i, n := range nodes {
+			// The last occurrence of a key holds its value.
+			if n.parent == parent && n.key == segment {
+				next = i
+			}
+		}
+		parent = next
+	}
+	positions := map[string]blockNode{}
+	for _, n := range nodes {
+		if _, ok := positions[n.key]; !ok && n.parent == parent && !n.entry {
+			positions[n.key] = n
+		}
+	}
+
+	entries := make([]MappingEntry, len(ms))
+	for i, item := range ms {
+		k, _ := keyToString(item.Key)
+		e := MappingEntry{Key: item.Key, Value: item.Value, Path: joinPath(path, k)}
+		if n, ok := positions[k]; ok {
+			e.Line, e.Column = n.line, n.column
+		}
+		entries[i] = e
+	}
+	return entries, nil
+}
+
+// childValue returns the child of the ordered value obj denoted by a path
+// segment: a mapping key or a "[i]" sequence index.
+func childValue(obj interface{}, segment string) (interface{}, error) {
+	if len(segment) > 2 && segment[0] == '[' && segment[len(segment)-1] == ']' {
+		s, ok := obj.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("%s applied to a non-sequence", segment)
+		}
+		i, err := strconv.Atoi(segment[1 : len(segment)-1])
+		if err != nil || i < 0 || i >= len(s) {
+			return nil, fmt.Errorf("index %s out of range", segment)
+		}
+		return s[i], nil
+	}
+	ms, ok := obj.(yaml.MapSlice)
+	if !ok {
+		return nil, fmt.Errorf("key %q looked up in a non-mapping", segment)
+	}
+	for _, item := range ms {
+		if k, ok := keyToString(item.Key); ok && k == segment {
+			return item.Value, nil
+		}
+	}
+	return nil, fmt.Errorf("key %q not found", segment)
+}
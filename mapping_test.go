@@ -0,0 +1,106 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMappingEntries(t *testing.T) {
+	doc := []byte(`defaults: &d
+  timeout: 5
+rules:
+- match: b
+  action: deny
+  <<: *d
+- {match: a, action: allow}
+zeta: 1
+alpha: 2
+`)
+
+	entries, err := MappingEntries(doc, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var keys []interface{}
+	for _, e := range entries {
+		keys = append(keys, e.Key)
+	}
+	if want := []interface{}{"defaults", "rules", "zeta", "alpha"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("keys = %v, want %v", keys, want)
+	}
+	if e := entries[3]; e.Path != "alpha" || e.Line != 9 || e.Column != 1 || e.Value != 2 {
+		t.Errorf("entry = %+v, want alpha at 9:1 with value 2", e)
+	}
+
+	entries, err = MappingEntries(doc, "rules[0]")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []MappingEntry{
+		{Key: "match", Value: "b", Path: "rules[0].match", Line: 4, Column: 3},
+		{Key: "action", Value: "deny", Path: "rules[0].action", Line: 5, Column: 3},
+		{Key: "timeout", Value: 5, Path: "rules[0].timeout"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("MappingEntries() = %+v, want %+v", entries, want)
+	}
+
+	entries, err = MappingEntries(doc, "rules[1]")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = []MappingEntry{
+		{Key: "match", Value: "a", Path: "rules[1].match"},
+		{Key: "action", Value: "allow", Path: "rules[1].action"},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("MappingEntries() = %+v, want %+v", entries, want)
+	}
+
+	entries, err = MappingEntries(doc, "defaults")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 1 || !reflect.DeepEqual(entries[0].Value, 5) {
+		t.Errorf("MappingEntries() = %+v", entries)
+	}
+
+	// Entries are located by their own key, not by a path that a key
+	// containing "." could also spell.
+	doc = []byte("a.b: 1\na:\n  c: 2\n  b: 3\n")
+	entries, err = MappingEntries(doc, "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = []MappingEntry{
+		{Key: "c", Value: 2, Path: "a.c", Line: 3, Column: 3},
+		{Key: "b", Value: 3, Path: "a.b", Line: 4, Column: 3},
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("MappingEntries() = %+v, want %+v", entries, want)
+	}
+	entries, err = MappingEntries(doc, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 2 || entries[0].Line != 1 || entries[1].Line != 2 {
+		t.Errorf("MappingEntries() = %+v, want a.b at line 1 and a at line 2", entries)
+	}
+
+	// The positions of a duplicate key's mapping are those of its last
+	// occurrence, whose value is reported.
+	entries, err = MappingEntries([]byte("a:\n  x: 1\na:\n    z: 2\n"), "a")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = []MappingEntry{{Key: "z", Value: 2, Path: "a.z", Line: 4, Column: 5}}
+	if !reflect.DeepEqual(entries, want) {
+		t.Errorf("MappingEntries() = %+v, want %+v", entries, want)
+	}
+
+	for _, path := range []string{"zeta", "rules[2]", "missing", "rules.x"} {
+		if _, err := MappingEntries(doc, path); err == nil {
+			t.Errorf("MappingEntries(%q): expected error", path)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+package yaml
+
+import "reflect"
+
+// FieldMatcher decides which struct field a mapping key decodes into, for
+// keys that are not exactly the name of a field. See MatchFields.
+type FieldMatcher interface {
+	// MatchField reports whether the document key should decode into the
+	// struct field field, whose JSON name is name.
+	MatchField(key, name string, field reflect.StructField) bool
+}
+
+// FieldMatcherFunc adapts a function to the FieldMatcher interface.
+type FieldMatcherFunc func(key, name string, field reflect.StructField) bool
+
+// MatchField calls f(key, name, field).
+func (f FieldMatcherFunc) MatchField(key, name string, field reflect.StructField) bool {
+	return f(key, name, field)
+}
+
+// MatchFields makes UnmarshalWithOptions decode a mapping key that is not
+// exactly the name of a field of the target struct into the first field,
+// in the order of the JSON library, that m matches it with. It lets
+// integrators accept keys in other spellings, such as with Unicode
+// normalization or with a list of aliases per field, without renaming
+// them beforehand.
+//
+// Keys that m matches to no field are handled as without MatchFields: the
+// JSON library still matches them to a field case-insensitively, and strict
+// decoding rejects them otherwise. When several keys of a mapping set the
+// same field, the field's own key wins, then the smallest matched key;
+// strict decoding rejects the mapping instead. Marshaling is unaffected.
+func MatchFields(m FieldMatcher) Option {
+	return func(o *options) {
+		o.fieldMatcher = m
+	}
+}
+
+// matchField returns the JSON name of the field of the struct type t that
+// the matcher set by MatchFields matches key with, or key itself.
+func (c *converter) matchField(t reflect.Type, key string) string {
+	fields := c.fields(t)
+	if _, ok := fields.exact[key]; ok {
+		return key
+	}
+	for i := range fields.list {
+		f := &fields.list[i]
+		if c.opts.fieldMatcher.MatchField(key, f.name, t.FieldByIndex(f.index)) {
+			return f.name
+		}
+	}
+	return key
+}
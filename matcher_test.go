@@ -0,0 +1,121 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMatchFields(t *testing.T) {
+	type server struct {
+		MaxConns int    `json:"maxConns" aliases:"max_connections,maxconn"`
+		Host     string `json:"host"`
+		Port     int    `json:"port"`
+	}
+	ignoreSeparators := FieldMatcherFunc(func(key, name string, _ reflect.StructField) bool {
+		r := strings.NewReplacer("_", "", "-", "")
+		return strings.EqualFold(r.Replace(key), name)
+	})
+	aliases := FieldMatcherFunc(func(key, _ string, field reflect.StructField) bool {
+		for _, a := range strings.Split(field.Tag.Get("aliases"), ",") {
+			if a == key {
+				return true
+			}
+		}
+		return false
+	})
+
+	tests := []struct {
+		name    string
+		input   string
+		matcher FieldMatcher
+		want    server
+		wantErr bool
+	}{
+		{
+			name:    "separators ignored",
+			input:   "max_conns: 5\nhost: h\n",
+			matcher: ignoreSeparators,
+			want:    server{MaxConns: 5, Host: "h"},
+		},
+		{
+			name:    "aliases",
+			input:   "max_connections: 5\nport: 80\n",
+			matcher: aliases,
+			want:    server{MaxConns: 5, Port: 80},
+		},
+		{
+			name:    "exact names still match",
+			input:   "maxConns: 2\nhost: h\n",
+			matcher: aliases,
+			want:    server{MaxConns: 2, Host: "h"},
+		},
+		{
+			name:    "unmatched keys rejected by strict decoding",
+			input:   "max-connections: 5\n",
+			matcher: aliases,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got server
+			err := UnmarshalWithOptions([]byte(tt.input), &got, Strict(), MatchFields(tt.matcher))
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("UnmarshalWithOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatchFieldsCollision(t *testing.T) {
+	type server struct {
+		MaxConns int `json:"maxConns"`
+	}
+	ignoreSeparators := MatchFields(FieldMatcherFunc(func(key, name string, _ reflect.StructField) bool {
+		r := strings.NewReplacer("_", "", "-", "")
+		return strings.EqualFold(r.Replace(key), name)
+	}))
+	tests := []struct {
+		input     string
+		want      int
+		strictErr string
+	}{
+		{
+			input:     "max_conns: 1\nmax-conns: 2\n",
+			want:      2,
+			strictErr: `keys "max-conns" and "max_conns" both set field "maxConns"`,
+		},
+		{
+			input:     "max_conns: 1\nmaxConns: 2\n",
+			want:      2,
+			strictErr: `keys "maxConns" and "max_conns" both set field "maxConns"`,
+		},
+	}
+	for _, tt := range tests {
+		// The outcome must not depend on map iteration order.
+		for i := 0; i < 50; i++ {
+			var got server
+			if err := UnmarshalWithOptions([]byte(tt.input), &got, ignoreSeparators); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.MaxConns != tt.want {
+				t.Fatalf("UnmarshalWithOptions(%q) = %+v, want MaxConns %d", tt.input, got, tt.want)
+			}
+		}
+		var got server
+		err := UnmarshalWithOptions([]byte(tt.input), &got, Strict(), ignoreSeparators)
+		if err == nil || !strings.HasSuffix(err.Error(), tt.strictErr) {
+			t.Errorf("strict UnmarshalWithOptions(%q) error = %v, want %q", tt.input, err, tt.strictErr)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package yaml
+
+import (
+	"time"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Metrics describes a call of UnmarshalWithOptions, YAMLToJSONWithOptions or
+// MarshalWithOptions, for export to a monitoring system.
+type Metrics struct {
+	// Operation is "Unmarshal", "YAMLToJSON" or "Marshal".
+	Operation string
+	// Documents is the number of YAML documents decoded or emitted.
+	Documents int
+	// Bytes is the size of the YAML decoded or emitted.
+	Bytes int
+	// Duration is the time the call took.
+	Duration time.Duration
+	// StrictErrors is the number of duplicate or unknown fields rejected
+	// when decoding with Strict, plus the number of validation errors.
+	StrictErrors int
+	// Nodes is the number of nodes of the decoded document, mapping keys
+	// included, with aliases expanded. MaxDepth is the deepest nesting of
+	// mappings and sequences: 0 for a scalar document, 1 for a flat mapping.
+	// Aliases is the number of aliases expanded and StringifiedKeys the
+	// number of mapping keys that were not strings and were converted to
+	// strings. They let callers spot anomalous documents and set limits
+	// informed by their workloads, and are 0 for Marshal.
+	Nodes           int
+	MaxDepth        int
+	Aliases         int
+	StringifiedKeys int
+	// Err is the error returned by the call, if any.
+	Err error
+}
+
+// WithMetrics makes UnmarshalWithOptions, YAMLToJSONWithOptions and
+// MarshalWithOptions call record with the metrics of each call before
+// returning, so that services can
+// instrument YAML processing without wrapping every call site. record is
+// called on the caller's goroutine and should be fast.
+func WithMetrics(record func(Metrics)) Option {
+	return func(o *options) {
+		o.metrics = record
+	}
+}
+
+// withStats returns m with the statistics s.
+func (m Metrics) withStats(s decodeStats) Metrics {
+	m.Nodes, m.MaxDepth, m.Aliases, m.StringifiedKeys = s.nodes, s.maxDepth, s.aliases, s.stringifiedKeys
+	return m
+}
+
+// decodeStats holds the statistics of a decoded document reported in
+// Metrics.
+type decodeStats struct {
+	nodes, maxDepth, aliases, stringifiedKeys int
+}
+
+// statsOf returns the statistics of the document y, decoded by go-yaml into
+// obj.
+func statsOf(y []byte, obj interface{}) decodeStats {
+	var s decodeStats
+	s.count(obj, 0)
+	for _, t := range scanTokens(y) {
+		if t.kind == aliasToken {
+			s.aliases++
+		}
+	}
+	return s
+}
+
+// count adds the nodes of obj, found at depth, to s.
+func (s *decodeStats) count(obj interface{}, depth int) {
+	s.nodes++
+	switch v := obj.(type) {
+	case map[interface{}]interface{}:
+		s.deeper(depth + 1)
+		for k, e := range v {
+			s.nodes++
+			if _, ok := k.(string); !ok {
+				s.stringifiedKeys++
+			}
+			s.count(e, depth+1)
+		}
+	case yaml.MapSlice:
+		s.deeper(depth + 1)
+		for _, item := range v {
+			s.nodes++
+			if _, ok := item.Key.(string); !ok {
+				s.stringifiedKeys++
+			}
+			s.count(item.Value, depth+1)
+		}
+	case []interface{}:
+		s.deeper(depth + 1)
+		for _, e := range v {
+			s.count(e, depth+1)
+		}
+	}
+}
+
+// deeper records that the document reaches depth.
+func (s *decodeStats) deeper(depth int) {
+	if depth > s.maxDepth {
+		s.maxDepth = depth
+	}
+}
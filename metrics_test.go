@@ -0,0 +1,93 @@
+package yaml
+
+import (
+	"testing"
+)
+
+func TestWithMetrics(t *testing.T) {
+	var got []Metrics
+	record := WithMetrics(func(m Metrics) {
+		got = append(got, m)
+	})
+
+	var v struct {
+		A int `json:"a"`
+	}
+	y := []byte("a: 1\na: 2\nb: 3\n")
+	if err := UnmarshalWithOptions(y, &v, record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := UnmarshalWithOptions(y, &v, record, Strict()); err == nil {
+		t.Fatal("expected strict error")
+	}
+	if err := UnmarshalWithOptions([]byte("a: 1\nb: 3\n"), &v, record, Strict()); err == nil {
+		t.Fatal("expected strict error")
+	}
+	if _, err := MarshalWithOptions([]interface{}{1, 2}, record, JSONArrayAsDocuments()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []struct {
+		operation    string
+		documents    int
+		bytes        int
+		strictErrors int
+		err          bool
+	}{
+		{"Unmarshal", 1, len(y), 0, false},
+		{"Unmarshal", 1, len(y), 1, true},
+		{"Unmarshal", 1, 10, 1, true},
+		{"Marshal", 2, len("1\n---\n2\n"), 0, false},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("got %d reports, want %d", len(got), len(want))
+	}
+	for i, w := range want {
+		m := got[i]
+		if m.Operation != w.operation || m.Documents != w.documents || m.Bytes != w.bytes ||
+			m.StrictErrors != w.strictErrors || (m.Err != nil) != w.err || m.Duration < 0 {
+			t.Errorf("report %d = %+v, want %+v", i, m, w)
+		}
+	}
+}
+
+func TestWithMetricsStatistics(t *testing.T) {
+	var got Metrics
+	record := WithMetrics(func(m Metrics) {
+		got = m
+	})
+
+	y := []byte(`base: &base {a: 1, b: [x, y]}
+list:
+  - <<: *base
+    c: 2
+  - *base
+1: one
+true: yes
+`)
+	want := Metrics{Nodes: 31, MaxDepth: 4, Aliases: 2, StringifiedKeys: 2}
+
+	var v interface{}
+	if err := UnmarshalWithOptions(y, &v, record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Operation != "Unmarshal" || got.Nodes != want.Nodes || got.MaxDepth != want.MaxDepth ||
+		got.Aliases != want.Aliases || got.StringifiedKeys != want.StringifiedKeys {
+		t.Errorf("Unmarshal metrics = %+v, want %+v", got, want)
+	}
+
+	if _, err := YAMLToJSONWithOptions(y, record); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Operation != "YAMLToJSON" || got.Documents != 1 || got.Bytes != len(y) || got.Nodes != want.Nodes ||
+		got.MaxDepth != want.MaxDepth || got.Aliases != want.Aliases || got.StringifiedKeys != want.StringifiedKeys {
+		t.Errorf("YAMLToJSON metrics = %+v, want %+v", got, want)
+	}
+
+	if _, err := YAMLToJSONWithOptions([]byte("a: [1\n"), record); err == nil {
+		t.Fatal("expected error")
+	}
+	if got.Err == nil || got.Nodes != 0 {
+		t.Errorf("metrics of failed call = %+v", got)
+	}
+}
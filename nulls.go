@@ -0,0 +1,51 @@
+package yaml
+
+import (
+	"bytes"
+	"reflect"
+)
+
+// EmitNullAsEmpty makes MarshalWithOptions and JSONToYAMLWithOptions leave
+// the values of null mapping entries and sequence entries empty, as in "b:",
+// instead of writing them as "null". Both forms decode to null, and neither
+// is ever confused with an empty string, which is always written as "".
+func EmitNullAsEmpty() Option {
+	return func(o *options) {
+		o.emitNullAsEmpty = true
+	}
+}
+
+// emptyNulls removes the plain "null" scalars that go-yaml emits for nil
+// values from y. The result is checked to decode to the same values as y,
+// which is returned unchanged otherwise.
+func emptyNulls(y []byte) []byte {
+	var out bytes.Buffer
+	last := 0
+	for _, t := range scanTokens(y) {
+		if t.kind != plainToken || string(y[t.start:t.end]) != "null" ||
+			(t.end < len(y) && y[t.end] == ':') {
+			continue
+		}
+		start := t.start
+		if start > 0 && y[start-1] == ' ' {
+			// Drop the space separating the value from its key or dash.
+			start--
+		}
+		out.Write(y[last:start])
+		last = t.end
+	}
+	if last == 0 {
+		return y
+	}
+	out.Write(y[last:])
+
+	want, err := yamlUnmarshalAll(y)
+	if err != nil {
+		return y
+	}
+	got, err := yamlUnmarshalAll(out.Bytes())
+	if err != nil || !reflect.DeepEqual(got, want) {
+		return y
+	}
+	return out.Bytes()
+}
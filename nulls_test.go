@@ -0,0 +1,71 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEmptyVersusNull(t *testing.T) {
+	input := []byte("a: \"\"\nb:\nc: null\nd: [\"\", null, 'null']\n")
+	wantJSON := `{"a":"","b":null,"c":null,"d":["",null,"null"]}`
+
+	j, err := YAMLToJSON(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(j) != wantJSON {
+		t.Errorf("YAMLToJSON() = %s, want %s", j, wantJSON)
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{
+			name: "explicit null",
+			want: "a: \"\"\nb: null\nc: null\nd:\n- \"\"\n- null\n- \"null\"\n",
+		},
+		{
+			name: "empty null",
+			opts: []Option{EmitNullAsEmpty()},
+			want: "a: \"\"\nb:\nc:\nd:\n- \"\"\n-\n- \"null\"\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			y, err := JSONToYAMLWithOptions(j, tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(y) != tt.want {
+				t.Errorf("JSONToYAMLWithOptions() = %q, want %q", y, tt.want)
+			}
+			// The round trip must not change any value.
+			j2, err := YAMLToJSON(y)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(j2) != wantJSON {
+				t.Errorf("round trip = %s, want %s", j2, wantJSON)
+			}
+		})
+	}
+}
+
+func TestEmptyNulls(t *testing.T) {
+	tests := []struct {
+		input, want string
+	}{
+		{"null\n", "null\n"}, // an empty stream has no document at all
+		{"null: null\n", "null:\n"},
+		{"a: |\n  b: null\nc: null\n", "a: |\n  b: null\nc:\n"},
+		{"- - null\n  - x\n", "- -\n  - x\n"},
+		{"a: nullable\n", "a: nullable\n"},
+	}
+	for _, tt := range tests {
+		if got := emptyNulls([]byte(tt.input)); !reflect.DeepEqual(string(got), tt.want) {
+			t.Errorf("emptyNulls(%q) = %q, want %q", tt.input, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package yaml
+
+import (
+	"reflect"
+)
+
+// Option configures the behavior of the *WithOptions family of functions.
+// Options that do not apply to a particular conversion are ignored.
+type Option func(*options)
+
+// options holds the settings accumulated from a list of Options.
+type options struct {
+	// jsonArrayAsDocuments converts a top-level JSON array into a stream of
+	// YAML documents rather than a single YAML sequence.
+	jsonArrayAsDocuments bool
+
+	// keepHeaderComments and keepShebang retain the comments at the top of
+	// the stream when stripping comments.
+	keepHeaderComments bool
+	keepShebang        bool
+
+	// lineWidth, if not 0, is the width long scalars are folded at when
+	// emitting YAML, instead of go-yaml's 80 columns, or -1 to keep them on
+	// a single line.
+	lineWidth int
+
+	// jsonIndent indents the JSON output of YAMLToJSONWithOptions, if not
+	// empty.
+	jsonIndent string
+
+	// kindOrder lists the kinds of resources to place first in a bundle.
+	kindOrder []string
+
+	// cipher encrypts the values selected by encryptPaths or by struct tags
+	// when marshaling, and decrypts them when unmarshaling.
+	cipher       FieldCipher
+	encryptPaths []string
+
+	// redactPaths selects the values that Diff withholds.
+	redactPaths []string
+
+	// transcodeInput converts UTF-16 and UTF-32 input to UTF-8.
+	transcodeInput bool
+
+	// tolerateTabs expands tab indentation to tabWidth columns before
+	// decoding, reporting the lines it changed to tabWarn.
+	tolerateTabs bool
+	tabWidth     int
+	tabWarn      func(lines []int)
+
+	// disallowDuplicateAnchors rejects documents redefining an anchor.
+	disallowDuplicateAnchors bool
+
+	// disallowControlCharacters rejects strings holding control characters.
+	disallowControlCharacters bool
+
+	// detectTruncation rejects input that looks cut off.
+	detectTruncation bool
+
+	// disallowTrailingContent rejects content after the end of a document.
+	disallowTrailingContent bool
+
+	// disallowAliases, disallowMergeKeys and disallowCustomTags reject
+	// input using these features; maxInputSize and maxDepth limit its size
+	// and nesting, if positive.
+	disallowAliases    bool
+	disallowMergeKeys  bool
+	disallowCustomTags bool
+	maxInputSize       int
+	maxDepth           int
+
+	// versionKeys are the patterns of the keys whose float values decode as
+	// strings.
+	versionKeys []string
+
+	// jsonOpts configure the JSON decoding of UnmarshalWithOptions.
+	jsonOpts []JSONOpt
+
+	// strict makes UnmarshalWithOptions behave like UnmarshalStrict.
+	strict bool
+
+	// groupStrictErrors reports all strict errors, grouped by path.
+	groupStrictErrors bool
+
+	// metrics receives a report of every call; strictErrors counts the
+	// strict errors found during the current call and stats describes the
+	// document it decoded.
+	metrics      func(Metrics)
+	strictErrors int
+	stats        decodeStats
+
+	// logger receives the problems found while decoding.
+	logger Logger
+
+	// validate validates the decoded structs with their Validate method
+	// and validateFn.
+	validate   bool
+	validateFn func(v interface{}) error
+
+	// applyDefaults sets absent fields to the value of their default tag.
+	applyDefaults bool
+
+	// singleDocument rejects streams with more than one document.
+	singleDocument bool
+
+	// emptyDocuments is the policy for empty documents in streams, or 0
+	// for the default of each function.
+	emptyDocuments EmptyDocumentPolicy
+
+	// orderedMaps and numbers choose the types produced by UnmarshalAny.
+	orderedMaps bool
+	numbers     numberMode
+
+	// sourceName names the input in messages.
+	sourceName string
+
+	// ranges receives the positions of the entries of decoded documents.
+	ranges map[string]Range
+
+	// maxErrorWidth bounds the lines of error messages, if positive.
+	maxErrorWidth int
+
+	// emitNullAsEmpty leaves null values empty when emitting YAML.
+	emitNullAsEmpty bool
+
+	// fieldNameMapper maps the names of untagged struct fields to document
+	// keys; mappedNames caches the reverse mapping of each struct type.
+	fieldNameMapper func(name string) string
+	mappedNames     map[reflect.Type]map[string]string
+
+	// fieldMatcher matches document keys to struct fields.
+	fieldMatcher FieldMatcher
+
+	// floatFormat is the policy for writing floats, or 0 to leave them as
+	// each layer writes them.
+	floatFormat FloatFormat
+	// integerDigits writes integer-valued floats in full digits.
+	integerDigits bool
+
+	// scalarParsers are the parsers given to ParseScalars, by target type.
+	scalarParsers map[reflect.Type]func(string) (interface{}, error)
+
+	// enums holds the allowed values of the types registered with Enum.
+	enums map[reflect.Type]*enumType
+}
+
+// newOptions applies opts, in order, on top of the default settings.
+func newOptions(opts ...Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Strict makes UnmarshalWithOptions reject duplicate keys and fields unknown
+// to the target, like UnmarshalStrict, and YAMLToJSONWithOptions reject
+// duplicate keys, like YAMLToJSONStrict.
+func Strict() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// JSONOpts makes UnmarshalWithOptions configure the JSON decoding of the
+// converted document with opts, like the JSONOpt arguments of Unmarshal.
+func JSONOpts(opts ...JSONOpt) Option {
+	return func(o *options) {
+		o.jsonOpts = append(o.jsonOpts, opts...)
+	}
+}
+
+// JSONArrayAsDocuments makes JSONToYAMLWithOptions convert a top-level JSON
+// array into a multi-document YAML stream with one document per element,
+// separated by "---". Nested arrays and non-array input are unaffected.
+func JSONArrayAsDocuments() Option {
+	return func(o *options) {
+		o.jsonArrayAsDocuments = true
+	}
+}
+
+// AsDocuments makes MarshalWithOptions emit a slice or array as a stream of
+// YAML documents, one per element, rather than as a YAML sequence, which is
+// what tools applying manifests expect. An empty slice produces an empty
+// stream. It is the same as JSONArrayAsDocuments, as seen from Go values.
+func AsDocuments() Option {
+	return JSONArrayAsDocuments()
+}
+
+// KeepHeaderComments makes StripComments keep the block of comment lines at
+// the very top of the stream, such as a license header. The block ends at
+// the first line that is not a comment.
+func KeepHeaderComments() Option {
+	return func(o *options) {
+		o.keepHeaderComments = true
+	}
+}
+
+// KeepShebang makes StripComments keep the first line of the stream if it
+// is a comment starting with "#!".
+func KeepShebang() Option {
+	return func(o *options) {
+		o.keepShebang = true
+	}
+}
+
+// DisableLineWrap makes MarshalWithOptions and JSONToYAMLWithOptions keep long
+// strings on a single line instead of folding them at 80 columns, which
+// keeps URLs and certificates readable and diffs small.
+func DisableLineWrap() Option {
+	return func(o *options) {
+		o.lineWidth = -1
+	}
+}
+
+// LineWidth makes MarshalWithOptions and JSONToYAMLWithOptions fold long
+// strings at width columns instead of 80. As with go-yaml's own folding, a
+// line is broken at the first single space past the width, so words are
+// never split and lines may run longer. A width of 0 or less keeps long
+// strings on a single line, as DisableLineWrap does.
+func LineWidth(width int) Option {
+	return func(o *options) {
+		if width <= 0 {
+			width = -1
+		}
+		o.lineWidth = width
+	}
+}
+
+// IndentJSON makes YAMLToJSONWithOptions write pretty-printed JSON, with each
+// member and element on its own line, indented by indent per level of
+// nesting, as json.MarshalIndent would write it. An empty indent keeps the
+// output compact.
+func IndentJSON(indent string) Option {
+	return func(o *options) {
+		o.jsonIndent = indent
+	}
+}
+
+// OrderByKind makes ComposeBundle place documents of the given kinds first,
+// in the order listed. Documents of other kinds follow, and documents of the
+// same rank keep their input order. See DefaultKindOrder for an order suited
+// to applying Kubernetes manifests.
+func OrderByKind(kinds ...string) Option {
+	return func(o *options) {
+		o.kindOrder = kinds
+	}
+}
+
+// DefaultKindOrder lists Kubernetes kinds in an order that satisfies common
+// dependencies when applying manifests: namespaces and definitions first,
+// then the objects that workloads reference, then the workloads.
+var DefaultKindOrder = []string{
+	"Namespace",
+	"CustomResourceDefinition",
+	"PriorityClass",
+	"StorageClass",
+	"ServiceAccount",
+	"Secret",
+	"ConfigMap",
+	"PersistentVolume",
+	"PersistentVolumeClaim",
+	"ClusterRole",
+	"ClusterRoleBinding",
+	"Role",
+	"RoleBinding",
+	"Service",
+	"DaemonSet",
+	"Pod",
+	"ReplicaSet",
+	"Deployment",
+	"StatefulSet",
+	"Job",
+	"CronJob",
+	"Ingress",
+}
@@ -0,0 +1,212 @@
+package yaml
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v2"
+)
+
+// yamlUnmarshalOrdered unmarshals the first YAML document in y into an
+// object whose mappings are yaml.MapSlices holding their keys in document
+// order. Keys brought in through merge keys ("<<") follow the mapping's own
+// keys, sorted. Duplicate keys keep the position of their first occurrence
+// and the value of their last one, like the unordered decode.
+func yamlUnmarshalOrdered(y []byte, yamlUnmarshal func([]byte, interface{}) error) (interface{}, error) {
+	var d orderedDocument
+	if err := yamlUnmarshal(y, &d); err != nil {
+		return nil, err
+	}
+	return applyOrder(d.content, d.order), nil
+}
+
+// orderedDocument decodes a YAML value twice from a single parse: content
+// with Go maps and order with yaml.MapSlices. go-yaml resolves merge keys
+// correctly only when decoding into Go maps; when decoding into MapSlices
+// the merged keys are dropped, but duplicate keys are kept in document order.
+type orderedDocument struct {
+	content interface{}
+	order   interface{}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *orderedDocument) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&d.content); err != nil {
+		return err
+	}
+	var err error
+	d.order, err = orderOf(d.content, unmarshal)
+	return err
+}
+
+// orderedValue decodes any YAML value such that all mappings within it,
+// including mappings nested in top-level sequences, become yaml.MapSlices.
+type orderedValue struct {
+	v interface{}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (o *orderedValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var probe interface{}
+	if err := unmarshal(&probe); err != nil {
+		return err
+	}
+	var err error
+	o.v, err = orderOf(probe, unmarshal)
+	return err
+}
+
+// orderOf decodes with unmarshal the value that decodes to v with Go maps
+// again, with yaml.MapSlices.
+func orderOf(v interface{}, unmarshal func(interface{}) error) (interface{}, error) {
+	switch v.(type) {
+	case map[interface{}]interface{}:
+		// Once go-yaml decodes into a MapSlice, it keeps using MapSlices for
+		// every mapping nested below it.
+		var m yaml.MapSlice
+		if err := unmarshal(&m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	case []interface{}:
+		var s []orderedValue
+		if err := unmarshal(&s); err != nil {
+			return nil, err
+		}
+		a := make([]interface{}, len(s))
+		for i := range s {
+			a[i] = s[i].v
+		}
+		return a, nil
+	}
+	return v, nil
+}
+
+// applyOrder converts the mappings in content into yaml.MapSlices ordered
+// like the corresponding mappings in order.
+func applyOrder(content, order interface{}) interface{} {
+	switch c := content.(type) {
+	case map[interface{}]interface{}:
+		ms := make(yaml.MapSlice, 0, len(c))
+		done := make(map[interface{}]bool, len(c))
+		if o, ok := order.(yaml.MapSlice); ok {
+			for _, item := range o {
+				v, ok := c[item.Key]
+				if !ok || done[item.Key] {
+					continue
+				}
+				done[item.Key] = true
+				ms = append(ms, yaml.MapItem{Key: item.Key, Value: applyOrder(v, item.Value)})
+			}
+		}
+		var merged []interface{}
+		for k := range c {
+			if !done[k] {
+				merged = append(merged, k)
+			}
+		}
+		sort.Slice(merged, func(i, j int) bool {
+			return fmt.Sprint(merged[i]) < fmt.Sprint(merged[j])
+		})
+		for _, k := range merged {
+			ms = append(ms, yaml.MapItem{Key: k, Value: applyOrder(c[k], nil)})
+		}
+		return ms
+	case []interface{}:
+		o, _ := order.([]interface{})
+		a := make([]interface{}, len(c))
+		for i := range c {
+			var oi interface{}
+			if i < len(o) {
+				oi = o[i]
+			}
+			a[i] = applyOrder(c[i], oi)
+		}
+		return a
+	default:
+		return content
+	}
+}
+
+// writeOrderedJSON writes the JSON encoding of the JSON-compatible value v
+// to buf, with the members of objects in the order of the corresponding
+// mappings of order, a value decoded with yaml.MapSlices. Members missing
+// from order, such as those brought in by merge keys, follow, sorted.
+func writeOrderedJSON(buf *bytes.Buffer, v, order interface{}) error {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		// members holds the value of the last item of order for each key,
+		// so that looking one up does not scan order.
+		var members map[string]interface{}
+		if ms, ok := order.(yaml.MapSlice); ok {
+			members = make(map[string]interface{}, len(ms))
+			for _, item := range ms {
+				k, ok := keyToString(item.Key)
+				if !ok {
+					continue
+				}
+				if _, seen := members[k]; !seen {
+					if _, present := v[k]; present {
+						keys = append(keys, k)
+					}
+				}
+				members[k] = item.Value
+			}
+		}
+		done := make(map[string]bool, len(keys))
+		for _, k := range keys {
+			done[k] = true
+		}
+		n := len(keys)
+		for k := range v {
+			if !done[k] {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys[n:])
+
+		buf.WriteByte('{')
+		for i, k := range keys {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			kj, err := json.Marshal(k)
+			if err != nil {
+				return err
+			}
+			buf.Write(kj)
+			buf.WriteByte(':')
+			if err := writeOrderedJSON(buf, v[k], members[k]); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+		return nil
+	case []interface{}:
+		o, _ := order.([]interface{})
+		buf.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			var eo interface{}
+			if i < len(o) {
+				eo = o[i]
+			}
+			if err := writeOrderedJSON(buf, e, eo); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte(']')
+		return nil
+	}
+	j, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	buf.Write(j)
+	return nil
+}
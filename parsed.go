@@ -0,0 +1,138 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ParsedDocument is a YAML document parsed once, for programs that decode a
+// document into several types, or query it several ways, without scanning
+// its bytes again each time. It is created by ParseDocument.
+//
+// A ParsedDocument is immutable and safe for concurrent use.
+type ParsedDocument struct {
+	// src is the input, after the options that fix it up.
+	src []byte
+	// obj is the document as decoded by go-yaml, and value its
+	// JSON-compatible form.
+	obj   interface{}
+	value interface{}
+	opts  []Option
+	// texts holds the document with the source text of each scalar, for
+	// ParseScalars.
+	texts interface{}
+}
+
+// ParseDocument parses the first YAML document in y. The options apply as
+// with UnmarshalWithOptions: the input options, such as Strict and
+// SingleDocument, when parsing, and the others with each call to the
+// methods of the returned ParsedDocument.
+func ParseDocument(y []byte, opts ...Option) (*ParsedDocument, error) {
+	c := &converter{fields: cachedStructFields, opts: newOptions(opts...)}
+	d, err := c.parseDocument(y)
+	if err != nil {
+		return nil, c.opts.sourceError(err)
+	}
+	d.opts = append([]Option(nil), opts...)
+	return d, nil
+}
+
+func (c *converter) parseDocument(y []byte) (*ParsedDocument, error) {
+	y, err := c.checkInput(y)
+	if err != nil {
+		return nil, err
+	}
+	unmarshalFn := yaml.Unmarshal
+	if c.opts.strict {
+		unmarshalFn = yaml.UnmarshalStrict
+	}
+	d := &ParsedDocument{src: y}
+	if len(c.opts.scalarParsers) > 0 {
+		var sd scalarDocument
+		err = unmarshalFn(y, &sd)
+		d.obj, d.texts = sd.content, sd.texts
+	} else {
+		err = unmarshalFn(y, &d.obj)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error converting YAML to JSON: %v", err)
+	}
+	if d.value, err = defaultConverter.convertToJSONableObject(d.obj, nil); err != nil {
+		return nil, fmt.Errorf("error converting YAML to JSON: %v", err)
+	}
+	return d, nil
+}
+
+// Unmarshal decodes the document into o as UnmarshalWithOptions would.
+func (d *ParsedDocument) Unmarshal(o interface{}) error {
+	c := &converter{fields: cachedStructFields, opts: newOptions(d.opts...)}
+	return c.opts.sourceError(c.unmarshalParsed(d, o))
+}
+
+func (c *converter) unmarshalParsed(d *ParsedDocument, o interface{}) error {
+	vo := reflect.ValueOf(o)
+	c.texts = d.texts
+	c.strict = c.opts.strict
+	j, err := c.objectToJSON(d.obj, &vo)
+	if err != nil {
+		return fmt.Errorf("error converting YAML to JSON: %v", err)
+	}
+	if c.opts.strict {
+		err = c.decodeJSON(j, o, true, DisallowUnknownFields)
+	} else {
+		err = c.decodeJSON(j, o, false)
+	}
+	if err != nil {
+		return err
+	}
+	return c.checkDecoded(d.src, o)
+}
+
+// JSON returns the JSON form of the document, as YAMLToJSONWithOptions
+// would.
+func (d *ParsedDocument) JSON() ([]byte, error) {
+	c := &converter{fields: cachedStructFields, opts: newOptions(d.opts...)}
+	j, err := c.objectToJSON(d.obj, nil)
+	if err != nil {
+		return nil, c.opts.sourceError(err)
+	}
+	return j, nil
+}
+
+// Lookup returns the value at path in the document, in the form Unmarshal
+// gives an interface{}: map[string]interface{} for mappings, []interface{}
+// for sequences, and so on. The path joins mapping keys with "." and appends
+// "[i]" for sequence entries, as in "spec.containers[0].image"; the empty
+// path denotes the whole document. It returns false if there is no value at
+// path.
+//
+// The value is shared with the document and must not be modified.
+func (d *ParsedDocument) Lookup(path string) (interface{}, bool) {
+	v := d.value
+	for _, segment := range splitPath(path) {
+		if strings.HasPrefix(segment, "[") {
+			s, ok := v.([]interface{})
+			if !ok {
+				return nil, false
+			}
+			i, err := strconv.Atoi(strings.TrimSuffix(segment[1:], "]"))
+			if err != nil || i < 0 || i >= len(s) {
+				return nil, false
+			}
+			v = s[i]
+			continue
+		}
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if v, ok = m[segment]; !ok {
+			return nil, false
+		}
+	}
+	return v, true
+}
@@ -0,0 +1,168 @@
+package yaml
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+)
+
+const parsedInput = `
+apiVersion: v1
+kind: Pod
+metadata:
+  name: web
+  labels: {version: 1.5}
+spec:
+  containers:
+  - name: app
+    image: nginx
+    port: 8080
+`
+
+func TestParsedDocument(t *testing.T) {
+	d, err := ParseDocument([]byte(parsedInput))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var meta struct {
+		Kind     string `json:"kind"`
+		Metadata struct {
+			Name   string            `json:"name"`
+			Labels map[string]string `json:"labels"`
+		} `json:"metadata"`
+	}
+	if err := d.Unmarshal(&meta); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if meta.Kind != "Pod" || meta.Metadata.Name != "web" || meta.Metadata.Labels["version"] != "1.5" {
+		t.Errorf("Unmarshal() = %+v", meta)
+	}
+
+	var spec struct {
+		Metadata struct {
+			Labels map[string]float64 `json:"labels"`
+		} `json:"metadata"`
+		Spec struct {
+			Containers []struct {
+				Port string `json:"port"`
+			} `json:"containers"`
+		} `json:"spec"`
+	}
+	if err := d.Unmarshal(&spec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if spec.Metadata.Labels["version"] != 1.5 || spec.Spec.Containers[0].Port != "8080" {
+		t.Errorf("Unmarshal() = %+v", spec)
+	}
+
+	lookups := map[string]interface{}{
+		"kind":                     "Pod",
+		"metadata.labels":          map[string]interface{}{"version": 1.5},
+		"spec.containers[0].image": "nginx",
+		"spec.containers[0].port":  8080,
+	}
+	for path, want := range lookups {
+		got, ok := d.Lookup(path)
+		if !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("Lookup(%q) = %#v, %v, want %#v", path, got, ok, want)
+		}
+	}
+	for _, path := range []string{"status", "spec.containers[1]", "kind.name", "spec[0]"} {
+		if got, ok := d.Lookup(path); ok {
+			t.Errorf("Lookup(%q) = %#v, want none", path, got)
+		}
+	}
+
+	j, err := d.JSON()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want, _ := YAMLToJSON([]byte(parsedInput))
+	if string(j) != string(want) {
+		t.Errorf("JSON() = %s, want %s", j, want)
+	}
+}
+
+func TestParsedDocumentOptions(t *testing.T) {
+	if _, err := ParseDocument([]byte("a: 1\na: 2\n"), Strict()); err == nil {
+		t.Errorf("expected an error for a duplicate key")
+	}
+	if _, err := ParseDocument([]byte("a: 1\n---\nb: 2\n"), SingleDocument()); err == nil {
+		t.Errorf("expected an error for a second document")
+	}
+
+	d, err := ParseDocument([]byte("a: 1\nb: 2\n"), Strict(), SourceName("in.yaml"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var v struct {
+		A int `json:"a"`
+	}
+	err = d.Unmarshal(&v)
+	if want := `in.yaml: error unmarshaling JSON: while decoding JSON: json: unknown field "b"`; err == nil || err.Error() != want {
+		t.Errorf("Unmarshal() error = %v, want %q", err, want)
+	}
+}
+
+// TestParsedDocumentConcurrent decodes one ParsedDocument from several
+// goroutines; run it with -race.
+func TestParsedDocumentConcurrent(t *testing.T) {
+	d, err := ParseDocument([]byte(parsedInput))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var v map[string]interface{}
+			if err := d.Unmarshal(&v); err != nil {
+				t.Error(err)
+			}
+			d.Lookup("spec.containers[0].name")
+		}()
+	}
+	wg.Wait()
+}
+
+func BenchmarkParsedDocument(b *testing.B) {
+	type kind struct {
+		Kind string `json:"kind"`
+	}
+	type name struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+	}
+	data := []byte(parsedInput)
+	b.Run("Unmarshal", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var k kind
+			var n name
+			if err := Unmarshal(data, &k); err != nil {
+				b.Fatal(err)
+			}
+			if err := Unmarshal(data, &n); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+	b.Run("ParseDocument", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var k kind
+			var n name
+			d, err := ParseDocument(data)
+			if err != nil {
+				b.Fatal(err)
+			}
+			if err := d.Unmarshal(&k); err != nil {
+				b.Fatal(err)
+			}
+			if err := d.Unmarshal(&n); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
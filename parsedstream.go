@@ -0,0 +1,121 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// ParsedStream is a YAML stream parsed document by document, for editors
+// and language servers that keep a stream parsed as it is edited. It is
+// created by ParseStream and updated by Edit, which parses again only the
+// documents an edit touches.
+//
+// A ParsedStream is immutable and safe for concurrent use.
+type ParsedStream struct {
+	src    []byte
+	docs   []Document
+	parsed []*ParsedDocument
+	opts   []Option
+}
+
+// ParseStream parses each document of the YAML stream y as ParseDocument
+// would. Empty documents are skipped unless changed with EmptyDocuments. If
+// a document fails to parse, ParseStream returns a *DocumentError naming
+// it, with the positions of the stream.
+func ParseStream(y []byte, opts ...Option) (*ParsedStream, error) {
+	return parseStream(y, opts, nil, 0, 0, 0)
+}
+
+// Source returns the stream, converted to UTF-8 if TranscodeInput is given.
+// It must not be modified.
+func (s *ParsedStream) Source() []byte {
+	return s.src
+}
+
+// Len returns the number of documents of the stream.
+func (s *ParsedStream) Len() int {
+	return len(s.docs)
+}
+
+// Document returns the position and metadata of the i-th document and the
+// document parsed. Positions in the errors of the methods of the
+// ParsedDocument are relative to the document, which starts on the line
+// Document.Line of the stream.
+func (s *ParsedStream) Document(i int) (Document, *ParsedDocument) {
+	return s.docs[i], s.parsed[i]
+}
+
+// Edit returns the stream with the bytes from start to end replaced by text,
+// as for an edit in an editor. The documents the edit does not touch are not
+// parsed again. s itself is unchanged. If a document fails to parse, Edit
+// returns a *DocumentError naming it, with the positions of the new stream.
+func (s *ParsedStream) Edit(start, end int, text []byte) (*ParsedStream, error) {
+	if start < 0 || start > end || end > len(s.src) {
+		return nil, fmt.Errorf("yaml: edit range [%d:%d] out of range [0:%d]", start, end, len(s.src))
+	}
+	y := make([]byte, 0, len(s.src)-(end-start)+len(text))
+	y = append(y, s.src[:start]...)
+	y = append(y, text...)
+	y = append(y, s.src[end:]...)
+	return parseStream(y, s.opts, s, start, end, len(text))
+}
+
+// parseStream parses the documents of y, reusing those of prev, if not nil,
+// that lie outside the bytes from start to end of prev's source, replaced by
+// n bytes in y.
+func parseStream(y []byte, opts []Option, prev *ParsedStream, start, end, n int) (*ParsedStream, error) {
+	o := newOptions(opts...)
+	// The source is kept as UTF-8, so that the offsets of documents and
+	// edits are those of the text parsed.
+	y, err := o.transcodeStream(y)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := ScanDocuments(y, opts...)
+	if err != nil {
+		return nil, err
+	}
+	s := &ParsedStream{
+		src:    y,
+		docs:   docs,
+		parsed: make([]*ParsedDocument, len(docs)),
+		opts:   append([]Option(nil), opts...),
+	}
+
+	// Documents before the edit keep their offsets; those after it move by
+	// the change in length.
+	var reuse map[[2]int]*ParsedDocument
+	if prev != nil {
+		reuse = make(map[[2]int]*ParsedDocument, len(prev.docs))
+		for i, d := range prev.docs {
+			if d.End < start || d.Start > end {
+				reuse[[2]int{d.Start, d.End}] = prev.parsed[i]
+			}
+		}
+	}
+	delta := n - (end - start)
+	for i, d := range docs {
+		if reuse != nil {
+			key := [2]int{d.Start, d.End}
+			if d.Start > start+n {
+				key = [2]int{d.Start - delta, d.End - delta}
+			}
+			if p, ok := reuse[key]; ok && (d.End < start || d.Start > start+n) {
+				s.parsed[i] = p
+				continue
+			}
+		}
+		c := &converter{fields: cachedStructFields, opts: newOptions(opts...)}
+		p, err := c.parseDocument(d.Content)
+		if err != nil {
+			// Moving the lines of the error down by those before the
+			// content gives the positions of the stream. A document that
+			// fails to parse is not empty, so its content ends at d.End.
+			shift := d.Line - 1 + bytes.Count(y[d.Start:d.End-len(d.Content)], []byte("\n"))
+			return nil, &DocumentError{Document: d, Err: o.sourceError(shiftLines(err, shift))}
+		}
+		p.opts = s.opts
+		s.parsed[i] = p
+	}
+	return s, nil
+}
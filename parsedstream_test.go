@@ -0,0 +1,110 @@
+package yaml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParsedStreamEdit(t *testing.T) {
+	y := []byte(`a: 1
+---
+b: 2
+---
+c: 3
+`)
+	s, err := ParseStream(y)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Len() != 3 {
+		t.Fatalf("got %d documents, want 3", s.Len())
+	}
+
+	// Change "b: 2" to "b: 20".
+	pos := strings.Index(string(y), "2")
+	e, err := s.Edit(pos+1, pos+1, []byte("0"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := string(e.Source()); got != "a: 1\n---\nb: 20\n---\nc: 3\n" {
+		t.Errorf("got source %q", got)
+	}
+	for i, want := range []string{`{"a":1}`, `{"b":20}`, `{"c":3}`} {
+		d, p := e.Document(i)
+		j, err := p.JSON()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if string(j) != want {
+			t.Errorf("document %d = %s, want %s", i, j, want)
+		}
+		_, old := s.Document(i)
+		if reused := p == old; reused != (i != 1) {
+			t.Errorf("document %d reused = %v", i, reused)
+		}
+		if i == 2 && (d.Start != 15 || d.Line != 4) {
+			t.Errorf("document 2 at offset %d, line %d; want 15, 4", d.Start, d.Line)
+		}
+	}
+	if v, _ := s.parsed[1].Lookup("b"); v != 2 {
+		t.Errorf("edit changed the original stream: b = %v", v)
+	}
+
+	// Split the first document in two.
+	e, err = e.Edit(5, 5, []byte("---\nz: 0\n"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if e.Len() != 4 {
+		t.Fatalf("got %d documents, want 4", e.Len())
+	}
+	if v, _ := e.parsed[1].Lookup("z"); v != 0 {
+		t.Errorf("z = %v, want 0", v)
+	}
+
+	// Break the last document.
+	_, err = e.Edit(len(e.Source())-2, len(e.Source())-1, []byte("[3"))
+	de, ok := err.(*DocumentError)
+	if !ok {
+		t.Fatalf("got %v, want a *DocumentError", err)
+	}
+	if de.Document.Index != 3 || !strings.Contains(de.Error(), "line 7") {
+		t.Errorf("got %v", de)
+	}
+
+	if _, err := e.Edit(3, 1, nil); err == nil {
+		t.Error("expected an error for an invalid range")
+	}
+}
+
+func BenchmarkParsedStreamEdit(b *testing.B) {
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		sb.WriteString("---\nkind: ConfigMap\nmetadata:\n  name: cm\ndata:\n  key: value\n")
+	}
+	s, err := ParseStream([]byte(sb.String()))
+	if err != nil {
+		b.Fatal(err)
+	}
+	pos := strings.LastIndex(sb.String(), "value")
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.Edit(pos, pos+1, []byte("V")); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParseStream(b *testing.B) {
+	var sb strings.Builder
+	for i := 0; i < 1000; i++ {
+		sb.WriteString("---\nkind: ConfigMap\nmetadata:\n  name: cm\ndata:\n  key: value\n")
+	}
+	y := []byte(sb.String())
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseStream(y); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// Range locates a node in a YAML document. See RecordRanges.
+type Range struct {
+	// Start and End are the byte offsets of the node in the input, End
+	// excluded. Line and Column locate its start, 1-based, and EndLine is
+	// the line of its last byte.
+	Start   int
+	End     int
+	Line    int
+	Column  int
+	EndLine int
+}
+
+// RecordRanges makes UnmarshalWithOptions fill ranges, after a successful
+// decode, with the range of each entry of the block mappings and sequences
+// of the document, keyed by its path, so that tools can lead from a decoded
+// or validated value back to where it is written. Paths join mapping keys
+// with "." and append "[i]" for sequence entries, as in
+// "spec.containers[0].image", like the errors of WithValidation.
+//
+// The range of a mapping entry spans its key and its value, and the range
+// of a sequence entry its "-" and its value, comments on the lines of the
+// value included. Entries of flow collections ("{...}" and "[...]") are not
+// recorded, but the entry holding the collection is.
+//
+// The options that rewrite the input before decoding it, TranscodeInput,
+// TolerateTabIndentation and KeepVersionStrings, would shift the ranges
+// away from the caller's bytes, so UnmarshalWithOptions rejects them along
+// with RecordRanges.
+//
+// ranges is written by every decode, so the option must not be shared by
+// concurrent calls. A Codec rejects it.
+func RecordRanges(ranges map[string]Range) Option {
+	return func(o *options) {
+		o.ranges = ranges
+	}
+}
+
+// checkRanges returns an error if o asks for ranges along with an option
+// that rewrites the input.
+func (o *options) checkRanges() error {
+	var names []string
+	if o.transcodeInput {
+		names = append(names, "TranscodeInput")
+	}
+	if o.tolerateTabs {
+		names = append(names, "TolerateTabIndentation")
+	}
+	if len(o.versionKeys) > 0 {
+		names = append(names, "KeepVersionStrings")
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	return fmt.Errorf("yaml: RecordRanges cannot be used with %s", strings.Join(names, " or "))
+}
+
+// recordRanges adds the ranges of the block collection entries of the first
+// document of y to ranges.
+func recordRanges(y []byte, ranges map[string]Range) {
+	tokens := scanTokens(y)
+	nodes, err := blockNodes(y, tokens)
+	if err != nil || len(nodes) == 0 {
+		return
+	}
+	end := len(y)
+	for _, t := range tokens {
+		if t.kind == documentToken && t.start > nodes[0].start {
+			end = t.start
+			break
+		}
+	}
+
+	for i, n := range nodes {
+		// The entry ends where the next entry that is not part of its value
+		// starts: one to its left, or a sibling. A sequence at the column of
+		// a mapping key is the value of the key.
+		e := end
+		for _, next := range nodes[i+1:] {
+			if next.column < n.column || next.column == n.column && (n.entry || !next.entry) {
+				e = next.start - (next.column - 1)
+				break
+			}
+		}
+		for e > n.start && (isBlank(y[e-1]) || y[e-1] == '\n' || y[e-1] == '\r') {
+			e--
+		}
+		// The range of a duplicate key is overwritten by the next one,
+		// whose value is the one decoded.
+		ranges[n.path] = Range{
+			Start:   n.start,
+			End:     e,
+			Line:    n.line,
+			Column:  n.column,
+			EndLine: n.line + bytes.Count(y[n.start:e], []byte("\n")),
+		}
+	}
+}
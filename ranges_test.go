@@ -0,0 +1,92 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRecordRanges(t *testing.T) {
+	input := []byte(`apiVersion: v1 # the version
+spec:
+  containers:
+  - name: web
+    image: nginx
+    ports: [80, 443]
+
+  - name: sidecar
+  replicas: 3
+items:
+- a
+- b
+---
+other: 1
+`)
+	type container struct {
+		Name  string `json:"name"`
+		Image string `json:"image"`
+		Ports []int  `json:"ports"`
+	}
+	var v struct {
+		APIVersion string `json:"apiVersion"`
+		Spec       struct {
+			Containers []container `json:"containers"`
+			Replicas   int         `json:"replicas"`
+		} `json:"spec"`
+		Items []string `json:"items"`
+	}
+	ranges := map[string]Range{}
+	if err := UnmarshalWithOptions(input, &v, RecordRanges(ranges)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	text := map[string]string{}
+	for path, r := range ranges {
+		text[path] = string(input[r.Start:r.End])
+	}
+	want := map[string]string{
+		"apiVersion":               "apiVersion: v1 # the version",
+		"spec":                     "spec:\n  containers:\n  - name: web\n    image: nginx\n    ports: [80, 443]\n\n  - name: sidecar\n  replicas: 3",
+		"spec.containers":          "containers:\n  - name: web\n    image: nginx\n    ports: [80, 443]\n\n  - name: sidecar",
+		"spec.containers[0]":       "- name: web\n    image: nginx\n    ports: [80, 443]",
+		"spec.containers[0].name":  "name: web",
+		"spec.containers[0].image": "image: nginx",
+		"spec.containers[0].ports": "ports: [80, 443]",
+		"spec.containers[1]":       "- name: sidecar",
+		"spec.containers[1].name":  "name: sidecar",
+		"spec.replicas":            "replicas: 3",
+		"items":                    "items:\n- a\n- b",
+		"items[0]":                 "- a",
+		"items[1]":                 "- b",
+	}
+	if !reflect.DeepEqual(text, want) {
+		t.Errorf("got %q, want %q", text, want)
+	}
+	if r := ranges["spec.containers[0]"]; r.Line != 4 || r.Column != 3 || r.EndLine != 6 {
+		t.Errorf("spec.containers[0] = %+v, want lines 4 to 6 from column 3", r)
+	}
+
+	ranges = map[string]Range{}
+	if err := UnmarshalWithOptions([]byte("a: [1\n"), &v, RecordRanges(ranges)); err == nil {
+		t.Fatal("expected error")
+	}
+	if len(ranges) != 0 {
+		t.Errorf("got ranges %v after a failed decode", ranges)
+	}
+}
+
+func TestRecordRangesRewrittenInput(t *testing.T) {
+	var v interface{}
+	ranges := map[string]Range{}
+	err := UnmarshalWithOptions([]byte("a:\n\tb: 1\n"), &v, TolerateTabIndentation(2, nil), TranscodeInput(),
+		RecordRanges(ranges))
+	if want := "yaml: RecordRanges cannot be used with TranscodeInput or TolerateTabIndentation"; err == nil || err.Error() != want {
+		t.Errorf("UnmarshalWithOptions() error = %v, want %q", err, want)
+	}
+	if len(ranges) != 0 {
+		t.Errorf("got ranges %v", ranges)
+	}
+	err = UnmarshalWithOptions([]byte("version: 1.20\n"), &v, KeepVersionStrings(), RecordRanges(ranges))
+	if want := "yaml: RecordRanges cannot be used with KeepVersionStrings"; err == nil || err.Error() != want {
+		t.Errorf("UnmarshalWithOptions() error = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,173 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+)
+
+const (
+	// SafeMaxInputSize and SafeMaxDepth are the limits applied by
+	// SafeProfile.
+	SafeMaxInputSize = 3 << 20
+	SafeMaxDepth     = 100
+)
+
+// SafeProfile bundles the options suited to decoding untrusted input, such
+// as the bodies of admission webhook requests: it applies DisallowAliases,
+// DisallowMergeKeys and DisallowCustomTags, and limits the input to
+// SafeMaxInputSize bytes and SafeMaxDepth levels of nesting. Options given
+// after it override its limits.
+//
+// SafeProfile leaves timestamps alone, as this package never resolves
+// implicit ones: plain scalars such as 2001-12-14 decode as strings, even
+// into interface{}, and a time.Time target is set by encoding/json from an
+// RFC 3339 string, never from a YAML timestamp. There is no timestamp
+// resolution left to disable; the explicit "!!timestamp" tag is rejected by
+// DisallowCustomTags.
+func SafeProfile() Option {
+	return func(o *options) {
+		for _, opt := range []Option{
+			DisallowAliases(),
+			DisallowMergeKeys(),
+			DisallowCustomTags(),
+			MaxInputSize(SafeMaxInputSize),
+			MaxDepth(SafeMaxDepth),
+		} {
+			opt(o)
+		}
+	}
+}
+
+// DisallowAliases makes the decoding functions that take options reject
+// input holding anchors or aliases, which let a small document expand into
+// a very large one.
+func DisallowAliases() Option {
+	return func(o *options) {
+		o.disallowAliases = true
+	}
+}
+
+// DisallowMergeKeys makes the decoding functions that take options reject
+// input holding merge keys ("<<").
+func DisallowMergeKeys() Option {
+	return func(o *options) {
+		o.disallowMergeKeys = true
+	}
+}
+
+// DisallowCustomTags makes the decoding functions that take options reject
+// input holding tags other than the standard tags of the JSON-compatible
+// types: "!!str", "!!int", "!!float", "!!bool", "!!null", "!!seq" and
+// "!!map".
+func DisallowCustomTags() Option {
+	return func(o *options) {
+		o.disallowCustomTags = true
+	}
+}
+
+// MaxInputSize makes the decoding functions that take options reject input
+// longer than size bytes. A size of 0 removes the limit.
+func MaxInputSize(size int) Option {
+	return func(o *options) {
+		o.maxInputSize = size
+	}
+}
+
+// MaxDepth makes the decoding functions that take options reject documents
+// nesting collections more than depth levels deep. A depth of 0 removes the
+// limit.
+func MaxDepth(depth int) Option {
+	return func(o *options) {
+		o.maxDepth = depth
+	}
+}
+
+// standardTags are the tags DisallowCustomTags accepts.
+var standardTags = map[string]bool{
+	"!!str":   true,
+	"!!int":   true,
+	"!!float": true,
+	"!!bool":  true,
+	"!!null":  true,
+	"!!seq":   true,
+	"!!map":   true,
+}
+
+// checkSafeTokens returns an error locating the first anchor, alias, merge
+// key or custom tag of the YAML stream y that o disallows.
+func checkSafeTokens(y []byte, o *options) error {
+	var plainMerges []token
+	for _, t := range scanTokens(y) {
+		text := string(y[t.start:t.end])
+		var what string
+		switch {
+		case t.kind == anchorToken && o.disallowAliases:
+			what = "anchor"
+		case t.kind == aliasToken && o.disallowAliases:
+			what = "alias"
+		case t.kind == tagToken && o.disallowMergeKeys && mergeTags[text]:
+			what = "merge tag"
+		case t.kind == tagToken && o.disallowCustomTags && !standardTags[text]:
+			what = "tag"
+		case t.kind == plainToken && o.disallowMergeKeys && text == "<<" &&
+			bytes.HasPrefix(bytes.TrimLeft(y[t.end:], " \t"), []byte(":")):
+			what = "merge key"
+		case t.kind == plainToken && o.disallowMergeKeys && text == "<<":
+			// Other plain "<<" scalars, such as explicit keys ("? <<"), are
+			// left to mergedKey.
+			plainMerges = append(plainMerges, t)
+			continue
+		default:
+			continue
+		}
+		return safeTokenError(y, t, what)
+	}
+	if t, ok := mergedKey(y, plainMerges); ok {
+		return safeTokenError(y, t, "merge key")
+	}
+	return nil
+}
+
+func safeTokenError(y []byte, t token, what string) error {
+	return fmt.Errorf("yaml: line %d, column %d: %s %s is not allowed", t.line, t.column, what, y[t.start:t.end])
+}
+
+// mergeTags are the spellings of the tag go-yaml merges mappings for.
+var mergeTags = map[string]bool{
+	"!!merge":                    true,
+	"!<tag:yaml.org,2002:merge>": true,
+}
+
+// mergedKey returns the first of the plain "<<" scalars of y that go-yaml
+// resolves as a merge key. Rather than telling keys from values by their
+// syntax, each scalar is quoted in turn, which go-yaml never takes as a merge
+// key, and the documents decoded again: the scalar was merged if they change.
+func mergedKey(y []byte, plain []token) (token, bool) {
+	if len(plain) == 0 {
+		return token{}, false
+	}
+	want, wantErr := yamlUnmarshalAll(y)
+	for _, t := range plain {
+		quoted := make([]byte, 0, len(y)+2)
+		quoted = append(quoted, y[:t.start]...)
+		quoted = append(quoted, `"<<"`...)
+		quoted = append(quoted, y[t.end:]...)
+		got, err := yamlUnmarshalAll(quoted)
+		if (err == nil) != (wantErr == nil) || !reflect.DeepEqual(got, want) {
+			return t, true
+		}
+	}
+	return token{}, false
+}
+
+// checkDepth returns an error if obj, as decoded by go-yaml, nests
+// collections more than max levels deep.
+func checkDepth(obj interface{}, max int) error {
+	var s decodeStats
+	s.count(obj, 0)
+	if s.maxDepth > max {
+		return fmt.Errorf("yaml: document nesting depth %d exceeds the limit of %d", s.maxDepth, max)
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSafeProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		opts    []Option
+		wantErr string
+	}{
+		{
+			name:  "plain document",
+			input: "name: web\nports: [80, 443]\nwhen: 2001-12-14\nlabel: !!str 1.0\n",
+		},
+		{
+			name:    "anchor",
+			input:   "a: &x 1\n",
+			wantErr: "yaml: line 1, column 4: anchor &x is not allowed",
+		},
+		{
+			name:    "alias",
+			input:   "a: [*x]\n",
+			wantErr: "yaml: line 1, column 5: alias *x is not allowed",
+		},
+		{
+			name:    "block merge key",
+			input:   "a:\n  <<: {b: 1}\n",
+			wantErr: "yaml: line 2, column 3: merge key << is not allowed",
+		},
+		{
+			name:    "flow merge key",
+			input:   "a: {<< : {b: 1}}\n",
+			wantErr: "yaml: line 1, column 5: merge key << is not allowed",
+		},
+		{
+			name:    "explicit merge key",
+			input:   "a:\n  ? <<\n  : {b: 1}\n",
+			wantErr: "yaml: line 2, column 5: merge key << is not allowed",
+		},
+		{
+			name:    "explicit merge key in flow mapping",
+			input:   "a: {? << : {b: 1}}\n",
+			wantErr: "yaml: line 1, column 7: merge key << is not allowed",
+		},
+		{
+			name:    "merge tag",
+			input:   "a: {!!merge '<<': {b: 1}}\n",
+			wantErr: "yaml: line 1, column 5: merge tag !!merge is not allowed",
+		},
+		{
+			name:  "quoted merge key",
+			input: "'<<': x\nb: <<\nc: [<<, {d: <<}]\n? e\n: <<\n",
+		},
+		{
+			name:    "custom tag",
+			input:   "a: !secret x\n",
+			wantErr: "yaml: line 1, column 4: tag !secret is not allowed",
+		},
+		{
+			name:    "timestamp tag",
+			input:   "a: !!timestamp 2001-12-14\n",
+			wantErr: "tag !!timestamp is not allowed",
+		},
+		{
+			name:    "anchor in JSON syntax",
+			input:   `{"a":&x [1],"c":*x}`,
+			wantErr: "yaml: line 1, column 6: anchor &x is not allowed",
+		},
+		{
+			name:    "alias in JSON syntax",
+			input:   `{"a":[1],"c":[2,*x]}`,
+			wantErr: "yaml: line 1, column 17: alias *x is not allowed",
+		},
+		{
+			name:    "tag in JSON syntax",
+			input:   `{"a":!foo 1}`,
+			wantErr: "yaml: line 1, column 6: tag !foo is not allowed",
+		},
+		{
+			name:    "tag in flow sequence",
+			input:   "a: [1,!foo 2]\n",
+			wantErr: "yaml: line 1, column 7: tag !foo is not allowed",
+		},
+		{
+			name:    "merge key in compact flow mapping",
+			input:   "a: {<<:{b: 1}}\n",
+			wantErr: "yaml: line 1, column 5: merge key << is not allowed",
+		},
+		{
+			name:  "JSON document",
+			input: `{"a":[1,{"b":"&x *y !z"}],"<<":{}}`,
+		},
+		{
+			name:    "too large",
+			input:   "a: " + strings.Repeat("x", SafeMaxInputSize) + "\n",
+			wantErr: "yaml: input of 3145732 bytes exceeds the limit of 3145728 bytes",
+		},
+		{
+			name:    "too deep",
+			input:   strings.Repeat("[", SafeMaxDepth+1) + strings.Repeat("]", SafeMaxDepth+1),
+			wantErr: "yaml: document nesting depth 101 exceeds the limit of 100",
+		},
+		{
+			name:  "limit overridden",
+			input: strings.Repeat("[", SafeMaxDepth+1) + strings.Repeat("]", SafeMaxDepth+1),
+			opts:  []Option{MaxDepth(0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{SafeProfile()}, tt.opts...)
+			var v interface{}
+			err := UnmarshalWithOptions([]byte(tt.input), &v, opts...)
+			_, anyErr := UnmarshalAny([]byte(tt.input), opts...)
+			for _, err := range []error{err, anyErr} {
+				if tt.wantErr == "" && err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+					t.Errorf("got error %v, want %q", err, tt.wantErr)
+				}
+			}
+		})
+	}
+}
+
+func TestSafeProfileDecodes(t *testing.T) {
+	var got struct {
+		Name  string `json:"name"`
+		When  string `json:"when"`
+		Ports []int  `json:"ports"`
+	}
+	err := UnmarshalWithOptions([]byte("name: web\nwhen: 2001-12-14\nports: [80]\n"), &got, SafeProfile())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Name != "web" || got.When != "2001-12-14" || !reflect.DeepEqual(got.Ports, []int{80}) {
+		t.Errorf("got %+v", got)
+	}
+}
+
+func TestSafeProfileTimestamps(t *testing.T) {
+	var got interface{}
+	if err := UnmarshalWithOptions([]byte("when: 2001-12-14t21:59:43.10-05:00\n"), &got, SafeProfile()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := map[string]interface{}{"when": "2001-12-14t21:59:43.10-05:00"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %#v, want %#v", got, want)
+	}
+}
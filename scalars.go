@@ -0,0 +1,150 @@
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+)
+
+// ParseScalars makes UnmarshalWithOptions decode scalars into values of the
+// type of v, or pointers to it, by calling parse with the text of the scalar.
+// It lets a document write quantities in units, such as "512Mi" or "250m",
+// for fields of a numeric user type:
+//
+//	type ByteSize int64
+//
+//	yaml.UnmarshalWithOptions(data, &cfg, yaml.ParseScalars(ByteSize(0), parseByteSize))
+//
+// parse is given the scalar as written, such as "1.10" rather than the 1.1
+// it resolves to, quotes and escapes removed.
+//
+// parse must return a value that encodes to JSON that the type of v decodes,
+// typically a value of that type. Null values are not passed to parse. Types
+// that implement json.Unmarshaler are parsed too, before their UnmarshalJSON
+// method would see the scalar. ParseScalars may be given several times, for
+// different types.
+func ParseScalars(v interface{}, parse func(s string) (interface{}, error)) Option {
+	t := reflect.TypeOf(v)
+	return func(o *options) {
+		if o.scalarParsers == nil {
+			o.scalarParsers = map[reflect.Type]func(string) (interface{}, error){}
+		}
+		o.scalarParsers[t] = parse
+	}
+}
+
+// parseScalar converts the scalar v, as decoded by go-yaml, with the parser
+// registered for the type t, if any. It returns false if there is none. The
+// parser is given text, the source text of v, if it is a string.
+func (c *converter) parseScalar(v, text interface{}, t reflect.Type) (interface{}, bool, error) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	parse, ok := c.opts.scalarParsers[t]
+	if !ok {
+		return nil, false, nil
+	}
+	s, ok := scalarText(v)
+	if !ok {
+		// Nulls, mappings and sequences are left to the JSON decoder.
+		return nil, false, nil
+	}
+	if text, ok := text.(string); ok {
+		s = text
+	}
+	parsed, err := parse(s)
+	if err != nil {
+		return nil, true, fmt.Errorf("cannot parse %q as %s: %v", s, t, err)
+	}
+	j, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, true, fmt.Errorf("cannot parse %q as %s: %v", s, t, err)
+	}
+	return json.RawMessage(j), true, nil
+}
+
+// scalarDocument decodes a YAML value twice from a single parse: content
+// as an interface{} and texts as scalarTexts.
+type scalarDocument struct {
+	content interface{}
+	texts   interface{}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (d *scalarDocument) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	if err := unmarshal(&d.content); err != nil {
+		return err
+	}
+	var t scalarTexts
+	if err := unmarshal(&t); err != nil {
+		return err
+	}
+	d.texts = t.v
+	return nil
+}
+
+// scalarTexts decodes a YAML value as an interface{} would be, but with
+// every scalar but null replaced by its source text, such as "1.10" for the
+// float 1.1: go-yaml decodes a scalar into a string as written.
+type scalarTexts struct {
+	v interface{}
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler.
+func (s *scalarTexts) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var probe interface{}
+	if err := unmarshal(&probe); err != nil {
+		return err
+	}
+	switch probe.(type) {
+	case nil:
+	case map[interface{}]interface{}:
+		var m map[interface{}]scalarTexts
+		if err := unmarshal(&m); err != nil {
+			return err
+		}
+		texts := make(map[interface{}]interface{}, len(m))
+		for k, v := range m {
+			texts[k] = v.v
+		}
+		s.v = texts
+	case []interface{}:
+		var a []scalarTexts
+		if err := unmarshal(&a); err != nil {
+			return err
+		}
+		texts := make([]interface{}, len(a))
+		for i := range a {
+			texts[i] = a[i].v
+		}
+		s.v = texts
+	default:
+		var text string
+		if err := unmarshal(&text); err != nil {
+			return err
+		}
+		s.v = text
+	}
+	return nil
+}
+
+// scalarText returns the text of the scalar v, as decoded by go-yaml, or
+// false if v is null, a mapping or a sequence.
+func scalarText(v interface{}) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
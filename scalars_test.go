@@ -0,0 +1,155 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+type byteSize int64
+
+func parseByteSize(s string) (interface{}, error) {
+	units := []struct {
+		suffix string
+		size   int64
+	}{{"Gi", 1 << 30}, {"Mi", 1 << 20}, {"Ki", 1 << 10}, {"", 1}}
+	for _, u := range units {
+		if strings.HasSuffix(s, u.suffix) {
+			n, err := strconv.ParseInt(strings.TrimSuffix(s, u.suffix), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid size")
+			}
+			return byteSize(n * u.size), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid size")
+}
+
+// milliCPU implements json.Unmarshaler, accepting only numbers of
+// millicores.
+type milliCPU struct {
+	Milli int64
+}
+
+func (m *milliCPU) UnmarshalJSON(b []byte) error {
+	n, err := strconv.ParseInt(string(b), 10, 64)
+	m.Milli = n
+	return err
+}
+
+func parseMilliCPU(s string) (interface{}, error) {
+	if strings.HasSuffix(s, "m") {
+		return strconv.ParseInt(strings.TrimSuffix(s, "m"), 10, 64)
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return int64(f * 1000), err
+}
+
+func TestParseScalars(t *testing.T) {
+	type limits struct {
+		Memory  byteSize            `json:"memory"`
+		Swap    *byteSize           `json:"swap"`
+		Caches  []byteSize          `json:"caches"`
+		Volumes map[string]byteSize `json:"volumes"`
+		CPU     milliCPU            `json:"cpu"`
+		Cores   milliCPU            `json:"cores"`
+		Unset   *byteSize           `json:"unset"`
+	}
+	data := []byte(`
+memory: 512Mi
+swap: 2Gi
+caches: [64Ki, 1024]
+volumes:
+  data: 1Gi
+cpu: 250m
+cores: 1.5
+unset: null
+`)
+	var got limits
+	err := UnmarshalWithOptions(data, &got,
+		ParseScalars(byteSize(0), parseByteSize),
+		ParseScalars(milliCPU{}, parseMilliCPU))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	swap := byteSize(2 << 30)
+	want := limits{
+		Memory:  512 << 20,
+		Swap:    &swap,
+		Caches:  []byteSize{64 << 10, 1024},
+		Volumes: map[string]byteSize{"data": 1 << 30},
+		CPU:     milliCPU{250},
+		Cores:   milliCPU{1500},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalWithOptions() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseScalarsError(t *testing.T) {
+	var got struct {
+		Memory byteSize `json:"memory"`
+	}
+	err := UnmarshalWithOptions([]byte("memory: lots\n"), &got, ParseScalars(byteSize(0), parseByteSize))
+	want := `error converting YAML to JSON: cannot parse "lots" as yaml.byteSize: invalid size`
+	if err == nil || err.Error() != want {
+		t.Errorf("UnmarshalWithOptions() error = %v, want %q", err, want)
+	}
+}
+
+// sourceText records the text ParseScalars gives its parser.
+type sourceText string
+
+func TestParseScalarsSourceText(t *testing.T) {
+	type spec struct {
+		Version sourceText            `json:"version"`
+		Mode    sourceText            `json:"mode"`
+		Quoted  sourceText            `json:"quoted"`
+		List    []sourceText          `json:"list"`
+		Labels  map[string]sourceText `json:"labels"`
+		Merged  map[string]sourceText `json:"merged"`
+		None    *sourceText           `json:"none"`
+	}
+	y := []byte(`version: 1.10
+mode: 0x1F
+quoted: "1.10"
+list: [1.0, +12, on]
+labels: {a: 1e3}
+base: &base {b: 2.50}
+merged:
+  <<: *base
+none: ~
+`)
+	opt := ParseScalars(sourceText(""), func(s string) (interface{}, error) {
+		return s, nil
+	})
+	want := spec{
+		Version: "1.10",
+		Mode:    "0x1F",
+		Quoted:  "1.10",
+		List:    []sourceText{"1.0", "+12", "on"},
+		Labels:  map[string]sourceText{"a": "1e3"},
+		Merged:  map[string]sourceText{"b": "2.50"},
+	}
+	var got spec
+	if err := UnmarshalWithOptions(y, &got, opt); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("UnmarshalWithOptions() = %+v, want %+v", got, want)
+	}
+
+	d, err := ParseDocument(y, opt)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got = spec{}
+	if err := d.Unmarshal(&got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParsedDocument.Unmarshal() = %+v, want %+v", got, want)
+	}
+}
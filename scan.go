@@ -0,0 +1,349 @@
+package yaml
+
+import (
+	"bytes"
+)
+
+// tokenKind identifies the lexical elements reported by scanTokens.
+type tokenKind int
+
+const (
+	commentToken tokenKind = iota
+	anchorToken
+	aliasToken
+	tagToken
+	// keyToken is the key of a block mapping entry, entryToken the "-" of a
+	// block sequence entry and documentToken a "---" marker.
+	keyToken
+	entryToken
+	documentToken
+	// plainToken, quotedToken and blockScalarToken span a whole plain,
+	// quoted or block scalar, the latter from its header to the end of its
+	// content.
+	plainToken
+	quotedToken
+	blockScalarToken
+)
+
+// token is a lexical element of a YAML stream. start and end are byte
+// offsets into the scanned input; line and column are 1-based.
+type token struct {
+	kind   tokenKind
+	start  int
+	end    int
+	line   int
+	column int
+	// ownLine is true for comments that are the only content of their line.
+	ownLine bool
+	// multiline is true for plain scalars continued on following lines.
+	multiline bool
+}
+
+// scanTokens returns the comments, anchors, aliases, tags, block collection
+// entries and document markers of the YAML stream y in input order.
+//
+// This is a lightweight lexer rather than a parser: it tracks just enough
+// context (quoted scalars, block scalars, flow collections) to tell, say, a
+// comment from a '#' inside a string. It never fails; on malformed input its
+// results are best-effort.
+func scanTokens(y []byte) []token {
+	s := &scanner{y: y, line: 1}
+	s.run()
+	return s.tokens
+}
+
+type scanner struct {
+	y      []byte
+	pos    int
+	line   int
+	bol    int // offset of the beginning of the current line
+	flow   int // flow collection nesting depth
+	tokens []token
+}
+
+func (s *scanner) emit(kind tokenKind, start, end int) {
+	s.emitAt(kind, start, end, s.line, s.bol)
+}
+
+// emitAt records a token starting on the given line, which begins at bol.
+func (s *scanner) emitAt(kind tokenKind, start, end, line, bol int) {
+	t := token{kind: kind, start: start, end: end, line: line, column: start - bol + 1}
+	if kind == commentToken {
+		t.ownLine = len(bytes.TrimLeft(s.y[bol:start], " \t")) == 0
+	}
+	s.tokens = append(s.tokens, t)
+}
+
+func (s *scanner) newline() {
+	s.pos++
+	s.line++
+	s.bol = s.pos
+}
+
+func isBlank(c byte) bool {
+	return c == ' ' || c == '\t'
+}
+
+func isBreakOrEnd(y []byte, i int) bool {
+	return i >= len(y) || y[i] == '\n' || y[i] == '\r'
+}
+
+func isBlankOrEnd(y []byte, i int) bool {
+	return isBreakOrEnd(y, i) || isBlank(y[i])
+}
+
+func (s *scanner) run() {
+	// boundary is true where a new node may begin, i.e. where a quote,
+	// block scalar indicator, anchor, alias or tag is significant.
+	boundary := true
+	// scalar is the extent of the last scalar seen on the current line, a
+	// candidate mapping key.
+	var scalar [2]int
+	// open is the index of the plain scalar token ending the previous line
+	// in block context, which the next line may continue, or -1.
+	open := -1
+	for s.pos < len(s.y) {
+		c := s.y[s.pos]
+		switch {
+		case c == '\n':
+			s.newline()
+			boundary = true
+			scalar = [2]int{}
+			continue
+		case isBlank(c) || c == '\r':
+			s.pos++
+			continue
+		case c == '#' && (s.pos == s.bol || isBlank(s.y[s.pos-1])):
+			start := s.pos
+			for !isBreakOrEnd(s.y, s.pos) {
+				s.pos++
+			}
+			s.emit(commentToken, start, s.pos)
+			open = -1
+			continue
+		}
+		cont := open
+		open = -1
+
+		if s.pos == s.bol && s.flow == 0 && (bytes.HasPrefix(s.y[s.pos:], []byte("---")) ||
+			bytes.HasPrefix(s.y[s.pos:], []byte("..."))) && isBlankOrEnd(s.y, s.pos+3) {
+			if s.y[s.pos] == '-' {
+				s.emit(documentToken, s.pos, s.pos+3)
+			}
+			s.pos += 3
+			boundary = true
+			continue
+		}
+
+		switch {
+		case (c == '-' || c == '?' || c == ':') && isBlankOrEnd(s.y, s.pos+1),
+			c == ':' && s.flow > 0:
+			// In flow collections ':' is taken as a value indicator even when
+			// not followed by a space, as in {"a":1}.
+			if s.flow == 0 {
+				switch {
+				case c == '-':
+					s.emit(entryToken, s.pos, s.pos+1)
+				case c == ':' && scalar[0] >= s.bol && scalar[1] > scalar[0]:
+					s.emit(keyToken, scalar[0], scalar[1])
+				}
+			}
+			s.pos++
+			boundary = true
+		case c == '[' || c == '{':
+			s.flow++
+			s.pos++
+			boundary = true
+		case c == ']' || c == '}':
+			if s.flow > 0 {
+				s.flow--
+			}
+			s.pos++
+			boundary = false
+		case c == ',' && s.flow > 0:
+			s.pos++
+			boundary = true
+		case c == '&' || c == '*' || c == '!':
+			// No scalar starts with these, so they are node properties or
+			// aliases wherever they begin a token, even where the input is
+			// malformed; checks relying on them must not be evaded.
+			start := s.pos
+			for !isBlankOrEnd(s.y, s.pos) && !(s.flow > 0 && isFlowIndicator(s.y[s.pos])) {
+				s.pos++
+			}
+			switch c {
+			case '&':
+				s.emit(anchorToken, start, s.pos)
+			case '*':
+				s.emit(aliasToken, start, s.pos)
+				boundary = false
+			default:
+				s.emit(tagToken, start, s.pos)
+			}
+		case boundary && (c == '\'' || c == '"'):
+			start, line, bol := s.pos, s.line, s.bol
+			s.quoted(c)
+			s.emitAt(quotedToken, start, s.pos, line, bol)
+			scalar = [2]int{start, s.pos}
+			boundary = false
+		case boundary && s.flow == 0 && (c == '|' || c == '>'):
+			// Emit the token ahead of any comment on the header line.
+			i := len(s.tokens)
+			s.emit(blockScalarToken, s.pos, s.pos)
+			s.blockScalar()
+			s.tokens[i].end = s.pos
+			boundary = true
+		default:
+			start := s.pos
+			s.plain()
+			if s.pos == start {
+				// Not a scalar after all; skip the character.
+				s.pos++
+				boundary = true
+				continue
+			}
+			end := s.pos
+			for end > start && isBlank(s.y[end-1]) {
+				end--
+			}
+			scalar = [2]int{start, end}
+			boundary = false
+			key := s.pos < len(s.y) && s.y[s.pos] == ':'
+			if cont >= 0 && !key && len(bytes.TrimLeft(s.y[s.bol:start], " \t")) == 0 {
+				s.tokens[cont].end = end
+				s.tokens[cont].multiline = true
+			} else {
+				cont = len(s.tokens)
+				s.emit(plainToken, start, end)
+			}
+			if s.flow == 0 && !key && isBreakOrEnd(s.y, s.pos) {
+				open = cont
+			}
+		}
+	}
+}
+
+func isFlowIndicator(c byte) bool {
+	return c == ',' || c == '[' || c == ']' || c == '{' || c == '}'
+}
+
+// quoted skips over a single- or double-quoted scalar, which may span lines.
+func (s *scanner) quoted(q byte) {
+	s.pos++
+	for s.pos < len(s.y) {
+		c := s.y[s.pos]
+		switch {
+		case c == '\n':
+			s.newline()
+			continue
+		case q == '"' && c == '\\':
+			s.pos++
+			if s.pos == len(s.y) {
+				return
+			}
+			if s.y[s.pos] == '\n' {
+				s.newline()
+				continue
+			}
+		case c == q:
+			if q == '\'' && s.pos+1 < len(s.y) && s.y[s.pos+1] == '\'' {
+				s.pos++
+			} else {
+				s.pos++
+				return
+			}
+		}
+		s.pos++
+	}
+}
+
+// plain skips over a plain scalar up to the end of the line or whatever
+// terminates it: a mapping value indicator, a comment or, in flow context, a
+// flow indicator.
+func (s *scanner) plain() {
+	for !isBreakOrEnd(s.y, s.pos) {
+		c := s.y[s.pos]
+		if c == ':' && (isBlankOrEnd(s.y, s.pos+1) || (s.flow > 0 && isFlowIndicator(s.y[s.pos+1]))) {
+			return
+		}
+		if c == '#' && isBlank(s.y[s.pos-1]) {
+			return
+		}
+		if s.flow > 0 && isFlowIndicator(c) {
+			return
+		}
+		s.pos++
+	}
+}
+
+// blockScalar skips over a literal or folded block scalar, from its header
+// to the last line of its content.
+func (s *scanner) blockScalar() {
+	// The content must be indented more than the node owning the scalar:
+	// the mapping key on the header line, failing that the innermost
+	// sequence entry, failing that the line itself.
+	parentIndent := -1
+	if !bytes.HasPrefix(s.y[s.bol:], []byte("---")) {
+		i := s.bol
+		for i < s.pos && s.y[i] == ' ' {
+			i++
+		}
+		parentIndent = i - s.bol
+		for i < s.pos && s.y[i] == '-' && isBlankOrEnd(s.y, i+1) {
+			parentIndent = i - s.bol
+			i++
+			for i < s.pos && s.y[i] == ' ' {
+				i++
+			}
+		}
+		if bytes.Contains(s.y[i:s.pos], []byte(": ")) {
+			parentIndent = i - s.bol
+		}
+	}
+
+	// Skip the rest of the header, reporting a trailing comment if any.
+	s.pos++
+	for !isBreakOrEnd(s.y, s.pos) {
+		if s.y[s.pos] == '#' && isBlank(s.y[s.pos-1]) {
+			start := s.pos
+			for !isBreakOrEnd(s.y, s.pos) {
+				s.pos++
+			}
+			s.emit(commentToken, start, s.pos)
+			break
+		}
+		s.pos++
+	}
+
+	indent := -1
+	for s.pos < len(s.y) {
+		// s.pos is at the line break ending the previous line.
+		if s.y[s.pos] == '\r' {
+			s.pos++
+			if s.pos == len(s.y) || s.y[s.pos] != '\n' {
+				return
+			}
+		}
+		next := s.pos + 1
+		i := next
+		for i < len(s.y) && s.y[i] == ' ' {
+			i++
+		}
+		if !isBreakOrEnd(s.y, i) {
+			n := i - next
+			if indent < 0 {
+				if n <= parentIndent {
+					return
+				}
+				indent = n
+			}
+			if n < indent {
+				return
+			}
+		}
+		s.newline()
+		for !isBreakOrEnd(s.y, s.pos) {
+			s.pos++
+		}
+	}
+}
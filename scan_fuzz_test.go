@@ -0,0 +1,27 @@
+// +build go1.18
+
+package yaml
+
+import "testing"
+
+func FuzzScanTokens(f *testing.F) {
+	for _, in := range compactJSON {
+		f.Add(in)
+	}
+	for _, in := range []string{
+		"a: b # c\n- [x, y]\n",
+		"k: |\n  text\n---\n'q': \"r\"\n",
+		"? &a !t x\n: *a\n",
+		"{a: b,: c, ::}",
+	} {
+		f.Add(in)
+	}
+	f.Fuzz(func(t *testing.T, in string) {
+		y := []byte(in)
+		for _, tok := range scanTokens(y) {
+			if tok.start < 0 || tok.start > tok.end || tok.end > len(y) || tok.line < 1 || tok.column < 1 {
+				t.Fatalf("scanTokens(%q): invalid token %+v", in, tok)
+			}
+		}
+	})
+}
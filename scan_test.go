@@ -0,0 +1,57 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+// compactJSON holds flow collections with ':' directly followed by a flow
+// indicator, which once made the scanner loop forever.
+var compactJSON = []string{
+	`{"metadata":{"name":"x"}}`,
+	`{"a":[1,{"b":{}}],"c":[]}`,
+	"spec: {\"template\":{\"spec\":[]}}\n",
+	`[{"a":{"b":[{"c":{}}]}}]`,
+}
+
+func TestScanTokensCompactJSON(t *testing.T) {
+	for _, in := range compactJSON {
+		y := []byte(in)
+		for _, tok := range scanTokens(y) {
+			if tok.start < 0 || tok.start > tok.end || tok.end > len(y) {
+				t.Errorf("scanTokens(%q): token %+v out of bounds", in, tok)
+			}
+		}
+		if _, err := StripComments(y); err != nil {
+			t.Errorf("StripComments(%q): %v", in, err)
+		}
+		if _, err := ExtractComments(y); err != nil {
+			t.Errorf("ExtractComments(%q): %v", in, err)
+		}
+		var v interface{}
+		err := UnmarshalWithOptions(append(y, '\n'), &v, DisallowDuplicateAnchors(), DisallowControlCharacters(),
+			DetectTruncation(), RecordRanges(map[string]Range{}), WithMetrics(func(Metrics) {}))
+		if err != nil {
+			t.Errorf("UnmarshalWithOptions(%q): %v", in, err)
+		}
+	}
+}
+
+func TestScanTokensFlowProperties(t *testing.T) {
+	y := []byte(`{"a":&x [1],"b":!t 2,"c":*x}`)
+	var kinds []tokenKind
+	var texts []string
+	for _, tok := range scanTokens(y) {
+		switch tok.kind {
+		case anchorToken, aliasToken, tagToken:
+			kinds = append(kinds, tok.kind)
+			texts = append(texts, string(y[tok.start:tok.end]))
+		}
+	}
+	if want := []tokenKind{anchorToken, tagToken, aliasToken}; !reflect.DeepEqual(kinds, want) {
+		t.Errorf("got kinds %v (%q), want %v", kinds, texts, want)
+	}
+	if want := []string{"&x", "!t", "*x"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("got %q, want %q", texts, want)
+	}
+}
@@ -0,0 +1,299 @@
+package yaml
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// InferJSONSchema infers a JSON Schema (draft-07) describing the example
+// YAML documents in docs, each of which may hold a stream of documents.
+//
+// Types observed for the same value across examples are merged, with
+// integers folded into numbers when both occur. Object properties missing
+// from some of the examples are optional; properties present in all of them
+// are listed as required. Keys are converted to strings as YAMLToJSON does.
+func InferJSONSchema(docs ...[]byte) ([]byte, error) {
+	root := &schemaNode{}
+	for _, doc := range docs {
+		objs, err := yamlUnmarshalAll(doc)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range objs {
+			j, err := defaultConverter.convertToJSONableObject(obj, nil)
+			if err != nil {
+				return nil, err
+			}
+			root.observe(j)
+		}
+	}
+	s := root.schema()
+	s["$schema"] = "http://json-schema.org/draft-07/schema#"
+	return json.Marshal(s)
+}
+
+// schemaNode accumulates the values observed at one location of the
+// example documents.
+type schemaNode struct {
+	types map[string]bool
+
+	// objects counts the objects observed, properties their members and
+	// seen how many of the objects had each member.
+	objects    int
+	properties map[string]*schemaNode
+	seen       map[string]int
+
+	// items accumulates the elements of the arrays observed.
+	items *schemaNode
+}
+
+func (n *schemaNode) observe(v interface{}) {
+	if n.types == nil {
+		n.types = map[string]bool{}
+	}
+	switch v := v.(type) {
+	case nil:
+		n.types["null"] = true
+	case bool:
+		n.types["boolean"] = true
+	case string:
+		n.types["string"] = true
+	case int, int64, uint64:
+		n.types["integer"] = true
+	case float64:
+		n.types["number"] = true
+	case []interface{}:
+		n.types["array"] = true
+		if n.items == nil {
+			n.items = &schemaNode{}
+		}
+		for _, e := range v {
+			n.items.observe(e)
+		}
+	case map[string]interface{}:
+		n.types["object"] = true
+		if n.properties == nil {
+			n.properties = map[string]*schemaNode{}
+			n.seen = map[string]int{}
+		}
+		n.objects++
+		for k, e := range v {
+			p, ok := n.properties[k]
+			if !ok {
+				p = &schemaNode{}
+				n.properties[k] = p
+			}
+			p.observe(e)
+			n.seen[k]++
+		}
+	}
+}
+
+func (n *schemaNode) schema() map[string]interface{} {
+	s := map[string]interface{}{}
+
+	if n.types["number"] {
+		delete(n.types, "integer")
+	}
+	var types []string
+	for t := range n.types {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	switch len(types) {
+	case 0:
+	case 1:
+		s["type"] = types[0]
+	default:
+		s["type"] = types
+	}
+
+	if n.properties != nil {
+		props := map[string]interface{}{}
+		var required []string
+		for k, p := range n.properties {
+			props[k] = p.schema()
+			if n.seen[k] == n.objects {
+				required = append(required, k)
+			}
+		}
+		s["properties"] = props
+		if len(required) > 0 {
+			sort.Strings(required)
+			s["required"] = required
+		}
+	}
+	if n.items != nil && len(n.items.types) > 0 {
+		s["items"] = n.items.schema()
+	}
+	return s
+}
+
+// YAMLToJSONWithSchema is like YAMLToJSONWithOptions, but uses the JSON
+// Schema schema the way Unmarshal uses its Go target: numbers and booleans
+// become strings where the schema expects a string and does not allow their
+// own type, so that a schema-driven consumer receives a port written 8080
+// for a string property as "8080", the value a Go program decoding into a
+// string field would see.
+//
+// The schema is followed through properties, additionalProperties, items,
+// additionalItems, allOf, anyOf, oneOf and local "$ref"s; the types allowed
+// at a location are those of all the subschemas that apply to it. Other
+// keywords are ignored, and the result is not validated against the schema.
+func YAMLToJSONWithSchema(y, schema []byte, opts ...Option) ([]byte, error) {
+	s, err := parseJSONSchema(schema)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON schema: %v", err)
+	}
+	c := &converter{fields: cachedStructFields, opts: newOptions(opts...), schema: s}
+	return c.yamlToJSONWithOptions(y)
+}
+
+// maxSchemaDepth bounds the references and combinations followed to find
+// the subschemas of a location, which may be cyclic.
+const maxSchemaDepth = 32
+
+// jsonSchema is a JSON Schema, decoded into JSON-compatible values.
+type jsonSchema struct {
+	root map[string]interface{}
+}
+
+func parseJSONSchema(schema []byte) (*jsonSchema, error) {
+	var root map[string]interface{}
+	if err := json.Unmarshal(schema, &root); err != nil {
+		return nil, err
+	}
+	return &jsonSchema{root: root}, nil
+}
+
+// coerce converts the numbers and booleans of the JSON-compatible value v
+// that schemas, the subschemas for its location, want as strings.
+func (s *jsonSchema) coerce(v interface{}, schemas []map[string]interface{}) interface{} {
+	schemas = s.expand(schemas)
+	if len(schemas) == 0 {
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, e := range v {
+			var sub []map[string]interface{}
+			for _, n := range schemas {
+				if p, ok := schemaProperty(n, k); ok {
+					sub = append(sub, p)
+				} else if ap, ok := n["additionalProperties"].(map[string]interface{}); ok {
+					sub = append(sub, ap)
+				}
+			}
+			v[k] = s.coerce(e, sub)
+		}
+	case []interface{}:
+		for i, e := range v {
+			var sub []map[string]interface{}
+			for _, n := range schemas {
+				switch items := n["items"].(type) {
+				case map[string]interface{}:
+					sub = append(sub, items)
+				case []interface{}:
+					if i < len(items) {
+						if m, ok := items[i].(map[string]interface{}); ok {
+							sub = append(sub, m)
+						}
+					} else if ai, ok := n["additionalItems"].(map[string]interface{}); ok {
+						sub = append(sub, ai)
+					}
+				}
+			}
+			v[i] = s.coerce(e, sub)
+		}
+	default:
+		if str := scalarString(v); str != "" && wantsString(v, schemas) {
+			return str
+		}
+	}
+	return v
+}
+
+func schemaProperty(n map[string]interface{}, key string) (map[string]interface{}, bool) {
+	props, _ := n["properties"].(map[string]interface{})
+	p, ok := props[key].(map[string]interface{})
+	return p, ok
+}
+
+// expand returns the schemas in with their references resolved, along with
+// the subschemas of their allOf, anyOf and oneOf keywords.
+func (s *jsonSchema) expand(in []map[string]interface{}) []map[string]interface{} {
+	var out []map[string]interface{}
+	var add func(n map[string]interface{}, depth int)
+	add = func(n map[string]interface{}, depth int) {
+		if n == nil || depth > maxSchemaDepth {
+			return
+		}
+		if ref, ok := n["$ref"].(string); ok {
+			// Keywords next to a reference are ignored in draft-07.
+			add(s.resolve(ref), depth+1)
+			return
+		}
+		out = append(out, n)
+		for _, kw := range []string{"allOf", "anyOf", "oneOf"} {
+			subs, _ := n[kw].([]interface{})
+			for _, sub := range subs {
+				m, _ := sub.(map[string]interface{})
+				add(m, depth+1)
+			}
+		}
+	}
+	for _, n := range in {
+		add(n, 0)
+	}
+	return out
+}
+
+// resolve returns the subschema the reference ref points to, or nil if it
+// is not a JSON pointer into the schema itself.
+func (s *jsonSchema) resolve(ref string) map[string]interface{} {
+	if !strings.HasPrefix(ref, "#") {
+		return nil
+	}
+	var n interface{} = s.root
+	for _, tok := range strings.Split(ref[1:], "/")[1:] {
+		tok = strings.Replace(strings.Replace(tok, "~1", "/", -1), "~0", "~", -1)
+		m, ok := n.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		n = m[tok]
+	}
+	m, _ := n.(map[string]interface{})
+	return m
+}
+
+// wantsString reports whether the schemas allow strings but not the type of
+// the number or boolean v.
+func wantsString(v interface{}, schemas []map[string]interface{}) bool {
+	types := map[string]bool{}
+	for _, n := range schemas {
+		switch t := n["type"].(type) {
+		case string:
+			types[t] = true
+		case []interface{}:
+			for _, e := range t {
+				if name, ok := e.(string); ok {
+					types[name] = true
+				}
+			}
+		}
+	}
+	if !types["string"] {
+		return false
+	}
+	switch v.(type) {
+	case bool:
+		return !types["boolean"]
+	case float64:
+		return !types["number"]
+	default:
+		return !types["number"] && !types["integer"]
+	}
+}
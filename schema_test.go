@@ -0,0 +1,113 @@
+package yaml
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestInferJSONSchema(t *testing.T) {
+	got, err := InferJSONSchema(
+		[]byte(`
+name: web
+replicas: 2
+ports: [80, 443]
+labels: {app: web}
+`),
+		[]byte(`
+name: db
+replicas: 1.5
+ports: []
+---
+name: cache
+replicas: null
+tags: [a, 1]
+`),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{
+		"$schema": "http://json-schema.org/draft-07/schema#",
+		"type": "object",
+		"properties": {
+			"name": {"type": "string"},
+			"replicas": {"type": ["null", "number"]},
+			"ports": {"type": "array", "items": {"type": "integer"}},
+			"labels": {"type": "object", "properties": {"app": {"type": "string"}}, "required": ["app"]},
+			"tags": {"type": "array", "items": {"type": ["integer", "string"]}}
+		},
+		"required": ["name", "replicas"]
+	}`
+	var gotObj, wantObj interface{}
+	if err := json.Unmarshal(got, &gotObj); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantObj); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(gotObj, wantObj) {
+		t.Errorf("InferJSONSchema() = %s, want %s", got, want)
+	}
+
+	if _, err := InferJSONSchema([]byte("a: [")); err == nil {
+		t.Error("expected error for invalid YAML")
+	}
+}
+
+func TestYAMLToJSONWithSchema(t *testing.T) {
+	schema := []byte(`{
+		"type": "object",
+		"definitions": {
+			"label": {"type": "string"}
+		},
+		"properties": {
+			"port": {"type": "string"},
+			"replicas": {"type": "integer"},
+			"enabled": {"type": "string"},
+			"version": {"type": ["string", "number"]},
+			"labels": {"type": "object", "additionalProperties": {"$ref": "#/definitions/label"}},
+			"args": {"type": "array", "items": {"type": "string"}},
+			"pair": {"type": "array", "items": [{"type": "integer"}, {"type": "string"}]},
+			"either": {"anyOf": [{"type": "string"}, {"type": "boolean"}]},
+			"nested": {"allOf": [{"properties": {"id": {"type": "string"}}}]}
+		}
+	}`)
+	y := []byte(`
+port: 8080
+replicas: 3
+enabled: true
+version: 1.5
+labels: {tier: 1, app: web}
+args: [--verbose, 2, 0.5]
+pair: [1, 2]
+either: false
+nested: {id: 7, other: 8}
+extra: 9
+`)
+	got, err := YAMLToJSONWithSchema(y, schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := `{"args":["--verbose","2","0.5"],"either":false,"enabled":"true","extra":9,` +
+		`"labels":{"app":"web","tier":"1"},"nested":{"id":"7","other":8},"pair":[1,"2"],` +
+		`"port":"8080","replicas":3,"version":1.5}`
+	if string(got) != want {
+		t.Errorf("YAMLToJSONWithSchema() = %s, want %s", got, want)
+	}
+
+	if _, err := YAMLToJSONWithSchema(y, []byte(`{`)); err == nil {
+		t.Errorf("expected an error for an invalid schema")
+	}
+}
+
+func TestYAMLToJSONWithSchemaCyclicRef(t *testing.T) {
+	schema := []byte(`{"$ref": "#/definitions/a", "definitions": {"a": {"allOf": [{"$ref": "#"}]}}}`)
+	got, err := YAMLToJSONWithSchema([]byte("a: 1\n"), schema)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"a":1}`; string(got) != want {
+		t.Errorf("YAMLToJSONWithSchema() = %s, want %s", got, want)
+	}
+}
@@ -0,0 +1,162 @@
+package yaml
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// SourceName names the stream or file being processed, for use in messages.
+// ScanDocuments records it in each Document, and UnmarshalWithOptions,
+// YAMLToJSONWithOptions and UnmarshalAny prefix the errors they return with
+// it, in the "name:line:column: message" form of compiler diagnostics. The
+// line and column are included when known.
+func SourceName(name string) Option {
+	return func(o *options) {
+		o.sourceName = name
+	}
+}
+
+// MaxErrorWidth makes the functions that honor SourceName bound each line of
+// their error messages to about width bytes, so that an error about a huge
+// single-line document, such as minified JSON, does not embed the document.
+// The longest quoted keys and values of an over-long line are shortened to
+// their start and end, joined by "...", and what remains too long is cut.
+// Messages are unbounded by default.
+func MaxErrorWidth(width int) Option {
+	return func(o *options) {
+		o.maxErrorWidth = width
+	}
+}
+
+// errorPosition matches the position go-yaml and this package put in error
+// messages.
+var errorPosition = regexp.MustCompile(`line (\d+)(?:, column (\d+))?: `)
+
+// sourceError prefixes the message of err with the source name and with the
+// position it mentions, if any, and bounds its width as set by
+// MaxErrorWidth. Each line of a message listing several errors is handled
+// separately.
+func (o *options) sourceError(err error) error {
+	if err != nil && o.sourceName != "" {
+		err = prefixSource(err, o.sourceName)
+	}
+	if err != nil && o.maxErrorWidth > 0 {
+		err = shortenError(err, o.maxErrorWidth)
+	}
+	return err
+}
+
+// prefixSource prefixes each line of the message of err with name and with
+// the position the line mentions, if any.
+func prefixSource(err error, name string) error {
+	lines := strings.Split(err.Error(), "\n")
+	for i, line := range lines {
+		indent := line[:len(line)-len(strings.TrimLeft(line, " "))]
+		line = line[len(indent):]
+		prefix := name
+		if m := errorPosition.FindStringSubmatchIndex(line); m != nil && (i == 0 || m[0] == 0) {
+			prefix += ":" + line[m[2]:m[3]]
+			if m[4] >= 0 {
+				prefix += ":" + line[m[4]:m[5]]
+			}
+			line = line[:m[0]] + line[m[1]:]
+		}
+		lines[i] = indent + prefix + ": " + line
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+// shortenError returns err, or an error with the same message with every
+// line bounded to width bytes if one is longer.
+func shortenError(err error, width int) error {
+	lines := strings.Split(err.Error(), "\n")
+	shortened := false
+	for i, line := range lines {
+		if len(line) > width {
+			lines[i] = shortenLine(line, width)
+			shortened = true
+		}
+	}
+	if !shortened {
+		return err
+	}
+	return errors.New(strings.Join(lines, "\n"))
+}
+
+// minQuoted is the number of bytes of a quoted key or value that
+// shortenLine keeps at least.
+const minQuoted = 16
+
+// shortenLine bounds line to width bytes, shortening its longest quoted
+// strings first.
+func shortenLine(line string, width int) string {
+	for len(line) > width {
+		start, end := longestQuoted(line)
+		inner := end - start - 2
+		if inner <= minQuoted {
+			break
+		}
+		keep := inner - (len(line) - width) - len("...")
+		if keep < minQuoted {
+			keep = minQuoted
+		}
+		head := cutBefore(line, start+1+keep/2)
+		tail := cutAfter(line, end-1-(keep-keep/2))
+		line = line[:head] + "..." + line[tail:]
+	}
+	if len(line) > width {
+		n := width - len("...")
+		if n < 0 {
+			n = 0
+		}
+		line = line[:cutBefore(line, n)] + "..."
+	}
+	return line
+}
+
+// longestQuoted returns the bounds, quotes included, of the longest string
+// of line quoted with double quotes or backquotes.
+func longestQuoted(line string) (int, int) {
+	bestStart, bestEnd := 0, 0
+	for i := 0; i < len(line); i++ {
+		q := line[i]
+		if q != '"' && q != '`' {
+			continue
+		}
+		j := i + 1
+		for j < len(line) && line[j] != q {
+			if q == '"' && line[j] == '\\' {
+				j++
+			}
+			j++
+		}
+		if j >= len(line) {
+			break
+		}
+		if j+1-i > bestEnd-bestStart {
+			bestStart, bestEnd = i, j+1
+		}
+		i = j
+	}
+	return bestStart, bestEnd
+}
+
+// cutBefore returns the largest offset of line up to i that neither splits
+// a UTF-8 sequence nor follows a backslash.
+func cutBefore(line string, i int) int {
+	for i > 0 && (!utf8.RuneStart(line[i]) || line[i-1] == '\\') {
+		i--
+	}
+	return i
+}
+
+// cutAfter returns the smallest offset of line from i that neither splits a
+// UTF-8 sequence nor follows a backslash.
+func cutAfter(line string, i int) int {
+	for i < len(line) && (!utf8.RuneStart(line[i]) || line[i-1] == '\\') {
+		i++
+	}
+	return i
+}
@@ -0,0 +1,115 @@
+package yaml
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestSourceName(t *testing.T) {
+	var v struct {
+		A int `json:"a"`
+	}
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		want  string
+	}{
+		{
+			name:  "syntax error",
+			input: "a: 1\nb: [\n",
+			want:  "x.yaml:2: error converting YAML to JSON: yaml: did not find expected node content",
+		},
+		{
+			name:  "strict errors",
+			input: "a: 1\na: 2\na: 3\n",
+			opts:  []Option{Strict()},
+			want: "x.yaml: error converting YAML to JSON: yaml: unmarshal errors:\n" +
+				"  x.yaml:2: key \"a\" already set in map\n" +
+				"  x.yaml:3: key \"a\" already set in map",
+		},
+		{
+			name:  "position with column",
+			input: "a: &x 1\nb: &x 2\n",
+			opts:  []Option{DisallowDuplicateAnchors()},
+			want:  `x.yaml:2:4: yaml: anchor "x" is already defined at line 1, column 4`,
+		},
+		{
+			name:  "no position",
+			input: "a: x\n",
+			want:  "x.yaml: error unmarshaling JSON: while decoding JSON: json: cannot unmarshal string into Go struct field .a of type int",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := UnmarshalWithOptions([]byte(tt.input), &v, append(tt.opts, SourceName("x.yaml"))...)
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("got error %v, want %s", err, tt.want)
+			}
+		})
+	}
+
+	_, err := YAMLToJSONWithOptions([]byte("a: [\n"), SourceName("y.yaml"))
+	if want := "y.yaml:1: yaml: did not find expected node content"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+	_, err = UnmarshalAny([]byte("a: [\n"), SourceName("z.yaml"))
+	if want := "z.yaml:1: error converting YAML to JSON: yaml: did not find expected node content"; err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+
+	o := newOptions()
+	if err := errors.New("line 1: x"); o.sourceError(err) != err {
+		t.Error("error changed without a source name")
+	}
+}
+
+func TestMaxErrorWidth(t *testing.T) {
+	key := strings.Repeat("k", 1000)
+	input := []byte(`{"` + key + `": 1, "` + key + `": 2, "b": [` + strings.Repeat("1, ", 1<<18) + `1]}`)
+
+	var v map[string]interface{}
+	err := UnmarshalWithOptions(input, &v, Strict(), MaxErrorWidth(80), SourceName("x.json"))
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	want := "x.json: error converting YAML to JSON: yaml: unmarshal errors:\n" +
+		`  x.json:1: key "kkkkkkkkkkkkkkkkkkkk...kkkkkkkkkkkkkkkkkkkk" already set in map`
+	if err.Error() != want {
+		t.Errorf("got\n%s\nwant\n%s", err, want)
+	}
+
+	short := errors.New(`key "abc" already set in map`)
+	if got := shortenError(short, 80); got != short {
+		t.Errorf("short error replaced by %v", got)
+	}
+
+	tests := []struct {
+		line  string
+		width int
+		want  string
+	}{
+		{
+			line:  "unknown field " + strconv.Quote(strings.Repeat("é", 40)) + " in T",
+			width: 40,
+			want:  `unknown field "éééé...éééé" in T`,
+		},
+		{
+			line:  `value "` + strings.Repeat(`\n`, 30) + `" rejected`,
+			width: 40,
+			want:  `value "\n\n\n\n\n...\n\n\n\n\n" rejected`,
+		},
+		{
+			line:  strings.Repeat("x", 50),
+			width: 20,
+			want:  strings.Repeat("x", 17) + "...",
+		},
+	}
+	for _, tt := range tests {
+		if got := shortenLine(tt.line, tt.width); got != tt.want {
+			t.Errorf("shortenLine(%q, %d) = %q, want %q", tt.line, tt.width, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,316 @@
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DocumentError is an error decoding one document of a YAML stream.
+type DocumentError struct {
+	// Document locates the document in the stream. Its Content and
+	// metadata are not filled in.
+	Document Document
+	Err      error
+}
+
+func (e *DocumentError) Error() string {
+	return e.Document.String() + ": " + e.Err.Error()
+}
+
+// DocumentErrors lists the documents of a YAML stream that failed to
+// decode, in stream order.
+type DocumentErrors []*DocumentError
+
+func (e DocumentErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, de := range e {
+		msgs[i] = de.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// UnmarshalDocuments decodes each document of the YAML stream y into a new
+// element appended to the slice pointed to by o, as UnmarshalWithOptions
+// would decode it on its own. Empty documents are skipped unless changed
+// with EmptyDocuments.
+//
+// A document that fails to decode does not stop the others from being
+// decoded: the slice gets an element for every document, and the errors are
+// returned together as DocumentErrors, each naming its document. Positions
+// in the errors are lines of the whole stream. With Strict, the duplicate
+// and unknown fields of a document are reported all at once, with the path
+// of each unknown field, as in:
+//
+//	document 2: strict decoding errors:
+//	  line 9: unknown field "spec.foo"
+//	  line 12: unknown field "spec.bar"
+func UnmarshalDocuments(y []byte, o interface{}, opts ...Option) error {
+	rv := reflect.ValueOf(o)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("yaml: UnmarshalDocuments needs a pointer to a slice, got %T", o)
+	}
+	opt := newOptions(opts...)
+	y, err := opt.transcodeStream(y)
+	if err != nil {
+		return opt.sourceError(err)
+	}
+	slice := rv.Elem()
+	var errs DocumentErrors
+	line, counted := 1, 0
+	for i, d := range splitDocuments(y) {
+		line += bytes.Count(y[counted:d.markerStart], []byte("\n"))
+		counted = d.markerStart
+		content := y[d.start:d.end]
+		if d.empty {
+			switch opt.emptyDocuments {
+			case NullEmptyDocuments:
+				content = []byte("null")
+			case RejectEmptyDocuments:
+				return opt.sourceError(emptyDocumentError(y, d))
+			default:
+				continue
+			}
+		}
+		doc := Document{
+			Index:  i,
+			Source: opt.sourceName,
+			Start:  d.markerStart,
+			End:    d.end,
+			Line:   line,
+		}
+		// The lines of error messages and warnings are moved down to those
+		// of the stream by the lines before the content.
+		shift := line - 1 + bytes.Count(y[d.markerStart:d.start], []byte("\n"))
+
+		ev := reflect.New(slice.Type().Elem())
+		c := &converter{fields: cachedStructFields, opts: newOptions(opts...)}
+		c.opts.shiftLines(shift)
+		if err := c.unmarshalWithOptions(content, ev.Interface()); err != nil {
+			if c.opts.strict && !c.opts.groupStrictErrors {
+				if serr := c.allStrictErrors(content, ev.Interface()); serr != nil {
+					err = serr
+				}
+			}
+			errs = append(errs, &DocumentError{Document: doc, Err: shiftLines(err, shift)})
+		}
+		slice = reflect.Append(slice, ev.Elem())
+	}
+	rv.Elem().Set(slice)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// messageLines matches the line numbers of error messages and warnings, as
+// in "line 9, column 3: " and "lines 9, 15, 21, ...".
+var messageLines = regexp.MustCompile(`\blines? \d+(?:, \d+)*`)
+
+// shiftLines returns err with the line numbers of its message moved down by
+// n lines, for an error about a document that starts after line n of its
+// stream.
+func shiftLines(err error, n int) error {
+	if err == nil || n == 0 {
+		return err
+	}
+	msg := messageLines.ReplaceAllStringFunc(err.Error(), func(s string) string {
+		return digits.ReplaceAllStringFunc(s, func(d string) string {
+			line, _ := strconv.Atoi(d)
+			return strconv.Itoa(line + n)
+		})
+	})
+	if msg == err.Error() {
+		return err
+	}
+	return errors.New(msg)
+}
+
+var digits = regexp.MustCompile(`\d+`)
+
+// shiftLines moves the lines reported to the logger and the tab warning of o
+// down by n lines, as shiftLines does for errors.
+func (o *options) shiftLines(n int) {
+	if n == 0 {
+		return
+	}
+	if o.logger != nil {
+		o.logger = shiftedLogger{o.logger, n}
+	}
+	if warn := o.tabWarn; warn != nil {
+		o.tabWarn = func(lines []int) {
+			warn(shiftedLines(lines, n))
+		}
+	}
+}
+
+// shiftedLogger is a Logger moving the "line" and "lines" values it is given
+// down by n lines.
+type shiftedLogger struct {
+	Logger
+	n int
+}
+
+func (l shiftedLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.Logger.Info(msg, l.shift(keysAndValues)...)
+}
+
+func (l shiftedLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.Logger.Error(err, msg, l.shift(keysAndValues)...)
+}
+
+func (l shiftedLogger) shift(keysAndValues []interface{}) []interface{} {
+	kv := append([]interface{}(nil), keysAndValues...)
+	for i := 0; i+1 < len(kv); i += 2 {
+		switch v := kv[i+1].(type) {
+		case int:
+			if kv[i] == "line" && v > 0 {
+				kv[i+1] = v + l.n
+			}
+		case []int:
+			if kv[i] == "lines" {
+				kv[i+1] = shiftedLines(v, l.n)
+			}
+		}
+	}
+	return kv
+}
+
+func shiftedLines(lines []int, n int) []int {
+	shifted := make([]int, len(lines))
+	for i, line := range lines {
+		shifted[i] = line + n
+	}
+	return shifted
+}
+
+// GroupStrictErrors makes UnmarshalWithOptions and UnmarshalDocuments, with
+// Strict, report all the duplicate and unknown fields of a document at once
+// and group those that differ only by sequence indexes, so that an unknown
+// field repeated in every element of a long list is reported once, with the
+// number of occurrences and the lines of the first few:
+//
+//	strict decoding errors:
+//	  line 9: unknown field "spec.containers[*].foo" (12 occurrences, lines 9, 15, 21, ...)
+func GroupStrictErrors() Option {
+	return func(o *options) {
+		o.groupStrictErrors = true
+	}
+}
+
+// allStrictErrors returns an error listing all the duplicate and unknown
+// fields of the YAML document y for decoding into the value pointed to by
+// o, grouped if requested, or nil if there are none.
+func (c *converter) allStrictErrors(y []byte, o interface{}) error {
+	msgs := c.strictDocumentErrors(y, o)
+	if len(msgs) == 0 {
+		return nil
+	}
+	if c.opts.groupStrictErrors {
+		msgs = groupStrictErrors(msgs)
+	}
+	return fmt.Errorf("strict decoding errors:\n  %s", strings.Join(msgs, "\n  "))
+}
+
+// maxGroupLines is the number of lines listed for a group of strict errors.
+const maxGroupLines = 3
+
+// sequenceIndex matches the sequence indexes of a path.
+var sequenceIndex = regexp.MustCompile(`\[\d+\]`)
+
+// groupStrictErrors merges the strict error messages msgs, sorted by line,
+// that are the same once their line and sequence indexes are removed.
+func groupStrictErrors(msgs []string) []string {
+	type group struct {
+		msg   string
+		lines []int
+	}
+	var groups []*group
+	byMsg := map[string]*group{}
+	for _, msg := range msgs {
+		line := messageLine(msg)
+		key := sequenceIndex.ReplaceAllString(strictErrorLine.ReplaceAllString(msg, ""), "[*]")
+		g, ok := byMsg[key]
+		if !ok {
+			g = &group{msg: msg}
+			byMsg[key] = g
+			groups = append(groups, g)
+		}
+		g.lines = append(g.lines, line)
+	}
+
+	out := make([]string, len(groups))
+	for i, g := range groups {
+		if len(g.lines) == 1 {
+			out[i] = g.msg
+			continue
+		}
+		msg := sequenceIndex.ReplaceAllString(g.msg, "[*]")
+		lines := make([]string, 0, maxGroupLines+1)
+		for j, line := range g.lines {
+			if j == maxGroupLines {
+				lines = append(lines, "...")
+				break
+			}
+			if line > 0 {
+				lines = append(lines, strconv.Itoa(line))
+			}
+		}
+		msg += fmt.Sprintf(" (%d occurrences", len(g.lines))
+		if len(lines) > 0 && lines[0] != "..." {
+			msg += ", lines " + strings.Join(lines, ", ")
+		}
+		out[i] = msg + ")"
+	}
+	return out
+}
+
+// strictDocumentErrors returns all the duplicate and unknown fields of the
+// YAML document y for decoding into the value pointed to by o, sorted by
+// line, rather than the first one the JSON decoder stops at.
+func (c *converter) strictDocumentErrors(y []byte, o interface{}) []string {
+	var yamlObj interface{}
+	var msgs []string
+	if err := yaml.UnmarshalStrict(y, &yamlObj); err != nil {
+		te, ok := err.(*yaml.TypeError)
+		if !ok {
+			return nil
+		}
+		msgs = append(msgs, te.Errors...)
+	}
+	obj, err := defaultConverter.convertToJSONableObject(yamlObj, nil)
+	if err != nil {
+		return nil
+	}
+	lines := keyLines(y)
+	c.walkFields(reflect.TypeOf(o), obj, "", func(path string, f *field) {
+		if f != nil {
+			return
+		}
+		msg := fmt.Sprintf("unknown field %q", path)
+		if line := locatePath(path, lines); line > 0 {
+			msg = "line " + strconv.Itoa(line) + ": " + msg
+		}
+		msgs = append(msgs, msg)
+	})
+	sort.SliceStable(msgs, func(i, j int) bool {
+		return messageLine(msgs[i]) < messageLine(msgs[j])
+	})
+	return msgs
+}
+
+// messageLine returns the line an error message starts with, or 0.
+func messageLine(msg string) int {
+	if m := strictErrorLine.FindStringSubmatch(msg); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return line
+	}
+	return 0
+}
@@ -0,0 +1,194 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+type streamResource struct {
+	Kind string `json:"kind"`
+	Spec struct {
+		Replicas int `json:"replicas"`
+	} `json:"spec"`
+}
+
+func TestUnmarshalDocuments(t *testing.T) {
+	y := []byte(`kind: A
+spec:
+  replicas: 1
+---
+---
+kind: B
+spec:
+  replicas: 2
+`)
+	var got []streamResource
+	if err := UnmarshalDocuments(y, &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got) != 2 || got[0].Kind != "A" || got[1].Spec.Replicas != 2 {
+		t.Errorf("UnmarshalDocuments() = %+v", got)
+	}
+
+	var ptrs []*streamResource
+	if err := UnmarshalDocuments(y, &ptrs, EmptyDocuments(NullEmptyDocuments)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(ptrs) != 3 || ptrs[1] != nil || ptrs[2].Kind != "B" {
+		t.Errorf("UnmarshalDocuments() with null documents = %+v", ptrs)
+	}
+
+	if err := UnmarshalDocuments(y, got); err == nil {
+		t.Errorf("expected an error for a slice that is not a pointer")
+	}
+}
+
+func TestUnmarshalDocumentsStrictErrors(t *testing.T) {
+	y := []byte(`kind: A
+spec:
+  replicas: 1
+---
+kind: B
+spec:
+  replicas: 2
+  foo: 1
+  bar: 2
+  replicas: 3
+---
+kind: C
+status: {}
+---
+kind: [D
+`)
+	var got []streamResource
+	err := UnmarshalDocuments(y, &got, Strict(), SourceName("bundle.yaml"))
+	errs, ok := err.(DocumentErrors)
+	if !ok {
+		t.Fatalf("UnmarshalDocuments() error = %#v, want DocumentErrors", err)
+	}
+	want := `document 2 of bundle.yaml: strict decoding errors:
+  line 8: unknown field "spec.foo"
+  line 9: unknown field "spec.bar"
+  line 10: key "replicas" already set in map
+document 3 of bundle.yaml: strict decoding errors:
+  line 13: unknown field "status"
+document 4 of bundle.yaml: error converting YAML to JSON: yaml: line 15: did not find expected ',' or ']'`
+	if err.Error() != want {
+		t.Errorf("UnmarshalDocuments() error:\n%s\nwant:\n%s", err, want)
+	}
+	var indexes []int
+	for _, e := range errs {
+		indexes = append(indexes, e.Document.Index)
+	}
+	if !reflect.DeepEqual(indexes, []int{1, 2, 3}) {
+		t.Errorf("failed documents = %v, want [1 2 3]", indexes)
+	}
+	if len(got) != 4 || got[0].Kind != "A" {
+		t.Errorf("UnmarshalDocuments() = %+v", got)
+	}
+}
+
+func TestGroupStrictErrors(t *testing.T) {
+	type container struct {
+		Name string `json:"name"`
+	}
+	type spec struct {
+		Containers []container `json:"containers"`
+	}
+	y := []byte(`spec:
+  containers:
+  - name: a
+    foo: 1
+  - name: b
+    foo: 2
+  - name: c
+    foo: 3
+  - name: d
+    foo: 4
+    bar: 5
+  extra: true
+`)
+
+	var v struct {
+		Spec spec `json:"spec"`
+	}
+	err := UnmarshalWithOptions(y, &v, Strict(), GroupStrictErrors(), SourceName("x.yaml"))
+	want := "x.yaml: strict decoding errors:\n" +
+		"  x.yaml:4: unknown field \"spec.containers[*].foo\" (4 occurrences, lines 4, 6, 8, ...)\n" +
+		"  x.yaml:11: unknown field \"spec.containers[3].bar\"\n" +
+		"  x.yaml:12: unknown field \"spec.extra\""
+	if err == nil || err.Error() != want {
+		t.Errorf("got error\n%v\nwant\n%s", err, want)
+	}
+
+	var docs []struct {
+		Spec spec `json:"spec"`
+	}
+	stream := append([]byte("spec: {}\n---\n"), y...)
+	err = UnmarshalDocuments(stream, &docs, Strict(), GroupStrictErrors())
+	want = "document 2: strict decoding errors:\n" +
+		"  line 6: unknown field \"spec.containers[*].foo\" (4 occurrences, lines 6, 8, 10, ...)\n" +
+		"  line 13: unknown field \"spec.containers[3].bar\"\n" +
+		"  line 14: unknown field \"spec.extra\""
+	if err == nil || err.Error() != want {
+		t.Errorf("got error\n%v\nwant\n%s", err, want)
+	}
+
+	msgs := groupStrictErrors([]string{
+		`line 2: key "a" already set in map`,
+		`line 3: key "a" already set in map`,
+		`unknown field "x[0]"`,
+		`unknown field "x[1]"`,
+	})
+	wantMsgs := []string{
+		`line 2: key "a" already set in map (2 occurrences, lines 2, 3)`,
+		`unknown field "x[*]" (2 occurrences)`,
+	}
+	if !reflect.DeepEqual(msgs, wantMsgs) {
+		t.Errorf("got %q, want %q", msgs, wantMsgs)
+	}
+}
+
+func TestUnmarshalDocumentsWarningLines(t *testing.T) {
+	var docs []struct {
+		Size int `json:"size" deprecated:""`
+	}
+	var tabLines []int
+	l := &recordingLogger{}
+	y := []byte("size: 1\n---\n# comment\nsize: 2\n---\nsize:\n\t3\n")
+	err := UnmarshalDocuments(y, &docs, WithLogger(l), TolerateTabIndentation(2, func(lines []int) {
+		tabLines = append(tabLines, lines...)
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{
+		"info: deprecated field [field size line 1]",
+		"info: deprecated field [field size line 4]",
+		"info: expanded tab indentation [lines [7]]",
+		"info: deprecated field [field size line 6]",
+	}
+	if !reflect.DeepEqual(l.entries, want) {
+		t.Errorf("logged %q, want %q", l.entries, want)
+	}
+	if !reflect.DeepEqual(tabLines, []int{7}) {
+		t.Errorf("tab warning for lines %v, want [7]", tabLines)
+	}
+}
+
+func BenchmarkUnmarshalDocuments(b *testing.B) {
+	var buf bytes.Buffer
+	for i := 0; i < 8000; i++ {
+		fmt.Fprintf(&buf, "---\nkind: K%d\nspec:\n  replicas: %d\n", i, i)
+	}
+	y := buf.Bytes()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var docs []streamResource
+		if err := UnmarshalDocuments(y, &docs); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package yaml
+
+import (
+	"bytes"
+	"regexp"
+)
+
+// blockScalarHeader matches a line ending with the header of a literal or
+// folded block scalar, such as "key: |" or "- >-  # comment".
+var blockScalarHeader = regexp.MustCompile(`(^|[\s:-])[|>][1-9+-]{0,2}\s*(#.*)?$`)
+
+// ExpandTabIndentation replaces the tabs used for indentation in y, which
+// YAML forbids, with spaces up to the next multiple of tabWidth columns, as
+// an editor displaying the file with that tab width would. It returns the
+// converted stream and the 1-based numbers of the lines it changed, which
+// are worth reporting since the result depends on the guessed tab width.
+//
+// Tabs after the first non-blank character of a line are left alone, and so
+// are tabs in the content of block scalars past their indentation.
+func ExpandTabIndentation(y []byte, tabWidth int) ([]byte, []int) {
+	if tabWidth < 1 {
+		tabWidth = 1
+	}
+	if bytes.IndexByte(y, '\t') < 0 {
+		return y, nil
+	}
+
+	var out bytes.Buffer
+	var lines []int
+	// blockParent holds the indentation of the line introducing the current
+	// block scalar, blockIndent the indentation of its content once known.
+	blockParent, blockIndent := -1, -1
+	for n, pos := 1, 0; pos < len(y); n++ {
+		end := lineEnd(y, pos)
+		line := y[pos:end]
+		pos = end + 1
+
+		// Expand the indentation, stopping at the content of a block scalar.
+		limit := -1
+		if blockParent >= 0 && blockIndent >= 0 {
+			limit = blockIndent
+		} else if blockParent >= 0 {
+			// Spaces alone may already indent the first line enough, in
+			// which case any tab after them is content.
+			spaces := len(line) - len(bytes.TrimLeft(line, " "))
+			if spaces > blockParent {
+				limit = spaces
+			}
+		}
+		var indent []byte
+		col, i, changed := 0, 0, false
+		for ; i < len(line) && (line[i] == ' ' || line[i] == '\t'); i++ {
+			if limit >= 0 && col >= limit {
+				break
+			}
+			if line[i] == ' ' {
+				indent = append(indent, ' ')
+				col++
+				continue
+			}
+			next := (col/tabWidth + 1) * tabWidth
+			for ; col < next; col++ {
+				indent = append(indent, ' ')
+			}
+			changed = true
+		}
+		rest := line[i:]
+		blank := len(bytes.TrimSpace(rest)) == 0
+
+		if blockParent >= 0 && !blank {
+			switch {
+			case blockIndent < 0 && col > blockParent:
+				blockIndent = col
+			case blockIndent < 0 || col < blockIndent:
+				blockParent, blockIndent = -1, -1
+			}
+		}
+		if blank {
+			// Blank lines neither end a block scalar nor need indenting.
+			changed = false
+			indent = line[:i]
+		}
+		if changed {
+			lines = append(lines, n)
+		}
+		out.Write(indent)
+		out.Write(rest)
+		if end < len(y) {
+			out.WriteByte('\n')
+		}
+
+		if blockParent < 0 && blockScalarHeader.Match(bytes.TrimRight(rest, "\r")) {
+			blockParent, blockIndent = col, -1
+		}
+	}
+	return out.Bytes(), lines
+}
+
+// TolerateTabIndentation makes UnmarshalWithOptions accept documents
+// indented with tabs by converting them with ExpandTabIndentation before
+// decoding. If any line was changed, warn, if not nil, is called with the
+// numbers of the changed lines.
+func TolerateTabIndentation(tabWidth int, warn func(lines []int)) Option {
+	return func(o *options) {
+		o.tolerateTabs = true
+		o.tabWidth = tabWidth
+		o.tabWarn = warn
+	}
+}
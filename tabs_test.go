@@ -0,0 +1,80 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpandTabIndentation(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		want      string
+		wantLines []int
+	}{
+		{
+			name:  "no tabs",
+			input: "a:\n  b: 1\n",
+			want:  "a:\n  b: 1\n",
+		},
+		{
+			name:      "tab indentation",
+			input:     "a:\n\tb: 1\n\tc:\n\t\t- x\t# tab kept\n",
+			want:      "a:\n  b: 1\n  c:\n    - x\t# tab kept\n",
+			wantLines: []int{2, 3, 4},
+		},
+		{
+			name:      "mixed with spaces",
+			input:     "a:\n \tb: 1\n  c: 2\n",
+			want:      "a:\n  b: 1\n  c: 2\n",
+			wantLines: []int{2},
+		},
+		{
+			name:      "block scalar content",
+			input:     "a: |\n\tx\n\t\ty\n\t\n\tz\nb: >-\n  \tw\n",
+			want:      "a: |\n  x\n  \ty\n\t\n  z\nb: >-\n  \tw\n",
+			wantLines: []int{2, 3, 5},
+		},
+		{
+			name:      "CRLF",
+			input:     "a:\r\n\tb: 1\r\n",
+			want:      "a:\r\n  b: 1\r\n",
+			wantLines: []int{2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, lines := ExpandTabIndentation([]byte(tt.input), 2)
+			if string(got) != tt.want {
+				t.Errorf("ExpandTabIndentation() = %q, want %q", got, tt.want)
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("ExpandTabIndentation() lines = %v, want %v", lines, tt.wantLines)
+			}
+		})
+	}
+}
+
+func TestTolerateTabIndentation(t *testing.T) {
+	y := []byte("a:\n\tb: 1\n\tc: |\n\t\tline\n")
+
+	var v map[string]map[string]interface{}
+	if err := UnmarshalWithOptions(y, &v); err == nil {
+		t.Fatal("expected error for tab indentation")
+	}
+
+	var warned []int
+	err := UnmarshalWithOptions(y, &v, TolerateTabIndentation(4, func(lines []int) {
+		warned = lines
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]map[string]interface{}{"a": {"b": float64(1), "c": "line\n"}}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("UnmarshalWithOptions() = %#v, want %#v", v, want)
+	}
+	if !reflect.DeepEqual(warned, []int{2, 3, 4}) {
+		t.Errorf("warned about lines %v, want [2 3 4]", warned)
+	}
+}
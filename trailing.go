@@ -0,0 +1,149 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DisallowTrailingContent makes the decoding functions that take options
+// reject a stream with content after the end of a document, such as a stray
+// "}" after a flow mapping or text after a "..." marker, which go-yaml
+// otherwise ignores, hiding corrupted or accidentally pasted input. Blank
+// lines and comments are allowed, as are further documents started with
+// "---". The error gives the position of the trailing content when it can
+// be found.
+func DisallowTrailingContent() Option {
+	return func(o *options) {
+		o.disallowTrailingContent = true
+	}
+}
+
+// checkTrailingContent returns an error if the YAML stream y has content
+// after the end of a document.
+func checkTrailingContent(y []byte) error {
+	d := yaml.NewDecoder(bytes.NewReader(y))
+	for {
+		var v interface{}
+		err := d.Decode(&v)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			if !strings.Contains(err.Error(), "did not find expected <document start>") {
+				// Other errors are for decoding the document to report.
+				return nil
+			}
+			if pos, ok := trailingContent(y); ok {
+				line := 1 + bytes.Count(y[:pos], []byte("\n"))
+				column := 1 + pos - (bytes.LastIndexByte(y[:pos], '\n') + 1)
+				return fmt.Errorf("yaml: line %d, column %d: found content after the end of the document", line, column)
+			}
+			return fmt.Errorf("found content after the end of the document: %v", err)
+		}
+	}
+}
+
+// trailingContent returns the offset of the first content of y following
+// the end of a document: either after a "..." marker without a "---" marker
+// to start a new document, or after a flow collection or quoted scalar
+// that is the whole document.
+func trailingContent(y []byte) (int, bool) {
+	for i, d := range splitDocuments(y) {
+		if d.empty {
+			continue
+		}
+		if i > 0 && !d.explicit {
+			return nextContent(y, d.start, d.end), true
+		}
+		end, ok := flowNodeEnd(y, nextContent(y, d.start, d.end), d.end)
+		if !ok {
+			continue
+		}
+		if pos := nextContent(y, end, d.end); pos < d.end {
+			return pos, true
+		}
+	}
+	return 0, false
+}
+
+// nextContent returns the offset of the first byte from start to end of y
+// that is neither blank nor part of a comment, or end.
+func nextContent(y []byte, start, end int) int {
+	for pos := start; pos < end; pos++ {
+		switch c := y[pos]; {
+		case c == '#' && (pos == start || isBlank(y[pos-1]) || y[pos-1] == '\n'):
+			pos = lineEnd(y, pos)
+		case !isBlank(c) && c != '\n' && c != '\r':
+			return pos
+		}
+	}
+	return end
+}
+
+// flowNodeEnd returns the offset following the flow collection or quoted
+// scalar starting at pos in y, after any tag and anchor, reporting false if
+// there is none or it does not end before end.
+func flowNodeEnd(y []byte, pos, end int) (int, bool) {
+	// Skip the node's properties.
+	for pos < end && (y[pos] == '!' || y[pos] == '&') {
+		for pos < end && !isBlankOrEnd(y, pos) {
+			pos++
+		}
+		pos = nextContent(y, pos, end)
+	}
+	if pos >= end {
+		return 0, false
+	}
+	switch y[pos] {
+	case '"', '\'':
+		return quotedEnd(y, pos, end)
+	case '{', '[':
+	default:
+		return 0, false
+	}
+	depth := 0
+	for pos < end {
+		switch c := y[pos]; {
+		case c == '{' || c == '[':
+			depth++
+		case c == '}' || c == ']':
+			if depth--; depth == 0 {
+				return pos + 1, true
+			}
+		case c == '"' || c == '\'' && (isBlank(y[pos-1]) || isFlowIndicator(y[pos-1]) || y[pos-1] == ':'):
+			// Apostrophes may appear within plain scalars.
+			e, ok := quotedEnd(y, pos, end)
+			if !ok {
+				return 0, false
+			}
+			pos = e
+			continue
+		case c == '#' && isBlank(y[pos-1]):
+			pos = lineEnd(y, pos)
+			continue
+		}
+		pos++
+	}
+	return 0, false
+}
+
+// quotedEnd returns the offset following the quoted scalar starting at pos
+// in y, reporting false if it does not end before end.
+func quotedEnd(y []byte, pos, end int) (int, bool) {
+	q := y[pos]
+	for i := pos + 1; i < end; i++ {
+		switch {
+		case q == '"' && y[i] == '\\':
+			i++
+		case y[i] == q && q == '\'' && i+1 < end && y[i+1] == '\'':
+			i++
+		case y[i] == q:
+			return i + 1, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,72 @@
+package yaml
+
+import (
+	"testing"
+)
+
+func TestDisallowTrailingContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "block mapping",
+			input: "a: 1\nb: [1, 2]\n# end\n",
+		},
+		{
+			name:  "flow mapping with comments",
+			input: "# head\n{\"a\": \"}\", b: 'it''s' } # done\n\n",
+		},
+		{
+			name:  "several documents",
+			input: "{a: 1}\n---\n[1]\n...\n---\nb: 2\n...\n# c\n",
+		},
+		{
+			name:    "stray brace",
+			input:   "{a: 1}\n}\n",
+			wantErr: "yaml: line 2, column 1: found content after the end of the document",
+		},
+		{
+			name:    "on the same line",
+			input:   "!!map &x {a: [1, \"]\"]} }\n",
+			wantErr: "yaml: line 1, column 24: found content after the end of the document",
+		},
+		{
+			name:    "after a quoted scalar",
+			input:   "---\n\"x\" # c\n  y\n",
+			wantErr: "yaml: line 3, column 3: found content after the end of the document",
+		},
+		{
+			name:    "after document end marker",
+			input:   "a: 1\n...\n# c\nb: 2\n",
+			wantErr: "yaml: line 4, column 1: found content after the end of the document",
+		},
+		{
+			name:    "in a later document",
+			input:   "a: 1\n---\n[1]]\n",
+			wantErr: "yaml: line 3, column 4: found content after the end of the document",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			err := UnmarshalWithOptions([]byte(tt.input), &v, DisallowTrailingContent())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				if err := UnmarshalWithOptions([]byte(tt.input), &v); err != nil {
+					t.Errorf("unexpected error without the option: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+			if _, err := YAMLToJSONWithOptions([]byte(tt.input)); err != nil {
+				t.Errorf("unexpected error without the option: %v", err)
+			}
+		})
+	}
+}
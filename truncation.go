@@ -0,0 +1,81 @@
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+)
+
+// DetectTruncation makes the decoding functions that take options reject
+// input that looks cut off, as by an interrupted download, instead of
+// decoding the part that arrived. go-yaml already rejects input ending
+// inside a quoted scalar or a flow collection, but input cut at the end of
+// a line of block YAML is usually still valid, and decodes to a partial
+// object. With DetectTruncation, the input must not end with a mapping key,
+// a sequence entry "-", an anchor or a tag still waiting for its value, nor
+// with a block scalar header still waiting for its content. A null value
+// must then be written explicitly, as "null" or "~". A missing final line
+// break alone is not taken as truncation, so that complete single-line
+// documents such as {"a":1} are accepted. The error gives the position
+// where the input ended.
+func DetectTruncation() Option {
+	return func(o *options) {
+		o.detectTruncation = true
+	}
+}
+
+// checkTruncation returns an error if the YAML stream y looks truncated.
+func checkTruncation(y []byte) error {
+	if len(bytes.TrimSpace(y)) == 0 {
+		return nil
+	}
+	tokens := scanTokens(y)
+	last := len(tokens) - 1
+	for last >= 0 && tokens[last].kind == commentToken {
+		last--
+	}
+	if last < 0 {
+		return nil
+	}
+	t := tokens[last]
+	from := t.end
+	if t.kind == keyToken {
+		from += bytes.IndexByte(y[from:], ':') + 1
+	}
+	if contentAfter(y, from, tokens[last+1:]) {
+		// The value is a flow collection, such as {} or [], which the
+		// scanner does not report.
+		return nil
+	}
+	var reason string
+	switch t.kind {
+	case keyToken:
+		reason = fmt.Sprintf("before the value of key %q", y[t.start:t.end])
+	case entryToken:
+		reason = "before the value of a sequence entry"
+	case anchorToken, tagToken:
+		reason = fmt.Sprintf("after %q, before the value it applies to", y[t.start:t.end])
+	case blockScalarToken:
+		text := y[t.start:t.end]
+		if i := bytes.IndexByte(text, '\n'); i < 0 || len(bytes.TrimSpace(text[i:])) == 0 {
+			reason = "before the content of a block scalar"
+		}
+	}
+	if reason == "" {
+		return nil
+	}
+	line := 1 + bytes.Count(y, []byte("\n"))
+	column := 1 + len(y) - (bytes.LastIndexByte(y, '\n') + 1)
+	return fmt.Errorf("yaml: line %d, column %d: input ends %s; it may be truncated", line, column, reason)
+}
+
+// contentAfter reports whether y holds anything other than blanks and the
+// comments among tokens from offset from on.
+func contentAfter(y []byte, from int, comments []token) bool {
+	for _, c := range comments {
+		if len(bytes.TrimSpace(y[from:c.start])) > 0 {
+			return true
+		}
+		from = c.end
+	}
+	return len(bytes.TrimSpace(y[from:])) > 0
+}
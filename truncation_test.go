@@ -0,0 +1,106 @@
+package yaml
+
+import (
+	"testing"
+)
+
+func TestDetectTruncation(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr string
+	}{
+		{
+			name:  "complete",
+			input: "spec:\n  replicas: 3\n  selector: null\n# end\n",
+		},
+		{
+			name:  "empty",
+			input: "",
+		},
+		{
+			name:  "trailing document marker",
+			input: "a: 1\n---\n",
+		},
+		{
+			name:  "block scalar",
+			input: "a: |\n  text\n",
+		},
+		{
+			name:  "empty flow mapping",
+			input: "resources: {}\n",
+		},
+		{
+			name:  "empty flow sequence",
+			input: "args: []\n",
+		},
+		{
+			name:  "flow collections after a comment",
+			input: "spec:\n  args: [] # none\n  env:\n  - {}\n",
+		},
+		{
+			name:  "anchored empty flow mapping",
+			input: "a: &x {}\n",
+		},
+		{
+			name:  "no final line break",
+			input: "spec:\n  replicas: 3",
+		},
+		{
+			name:  "single-line JSON",
+			input: `{"a":1}`,
+		},
+		{
+			name:  "single-line flow sequence",
+			input: "[a, b]",
+		},
+		{
+			name:    "missing value without a final line break",
+			input:   "spec:\n  template:",
+			wantErr: `yaml: line 2, column 12: input ends before the value of key "template"; it may be truncated`,
+		},
+		{
+			name:    "missing value",
+			input:   "spec:\n  replicas: 3\n  template:\n",
+			wantErr: `yaml: line 4, column 1: input ends before the value of key "template"; it may be truncated`,
+		},
+		{
+			name:    "missing value before a comment",
+			input:   "spec:\n  template: # pod\n",
+			wantErr: `yaml: line 3, column 1: input ends before the value of key "template"; it may be truncated`,
+		},
+		{
+			name:    "missing sequence entry",
+			input:   "args:\n- a\n-\n",
+			wantErr: "yaml: line 4, column 1: input ends before the value of a sequence entry; it may be truncated",
+		},
+		{
+			name:    "anchor",
+			input:   "a: &x\n",
+			wantErr: `yaml: line 2, column 1: input ends after "&x", before the value it applies to; it may be truncated`,
+		},
+		{
+			name:    "block scalar header",
+			input:   "a: |\n",
+			wantErr: "yaml: line 2, column 1: input ends before the content of a block scalar; it may be truncated",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v interface{}
+			if err := UnmarshalWithOptions([]byte(tt.input), &v); err != nil {
+				t.Fatalf("unexpected error without the option: %v", err)
+			}
+			err := UnmarshalWithOptions([]byte(tt.input), &v, DetectTruncation())
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("UnmarshalWithOptions() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,54 @@
+package yaml
+
+import "bytes"
+
+// maxRetainedBuffer is the capacity above which Reset releases the buffer of
+// an Unmarshaler rather than keeping it for later decodes.
+const maxRetainedBuffer = 64 << 10
+
+// Unmarshaler decodes YAML documents one after the other with the same
+// options, as UnmarshalWithOptions would, for programs that decode
+// repeatedly, such as controllers re-reading their configuration. The
+// options are processed once, and the buffer holding the intermediate JSON
+// of a document is reused by the next decode.
+//
+// Only that buffer is kept: go-yaml and encoding/json build their parser
+// state and decoded values afresh on every call, and account for nearly all
+// of its allocations, so an Unmarshaler saves about as much memory per
+// decode as the size of the document's JSON, not the allocations of
+// decoding it.
+//
+// An Unmarshaler is not safe for concurrent use.
+type Unmarshaler struct {
+	conv *converter
+	buf  bytes.Buffer
+}
+
+// NewUnmarshaler returns an Unmarshaler that honors the given options.
+func NewUnmarshaler(opts ...Option) *Unmarshaler {
+	u := &Unmarshaler{
+		conv: &converter{fields: cachedStructFields, opts: newOptions(opts...)},
+	}
+	u.conv.buf = &u.buf
+	return u
+}
+
+// Unmarshal is like UnmarshalWithOptions with the options of u.
+func (u *Unmarshaler) Unmarshal(y []byte, o interface{}) error {
+	u.conv.opts.strictErrors = 0
+	u.conv.opts.stats = decodeStats{}
+	return u.conv.unmarshal(y, o)
+}
+
+// Reset discards the state kept from earlier decodes, such as the field
+// names cached for MapFieldNames. The buffer is kept for the next decode
+// unless an unusually large document grew it beyond 64 KiB, in which case it
+// is released so that the Unmarshaler does not hold on to the memory.
+func (u *Unmarshaler) Reset() {
+	u.conv.opts.mappedNames = nil
+	if u.buf.Cap() > maxRetainedBuffer {
+		u.buf = bytes.Buffer{}
+	} else {
+		u.buf.Reset()
+	}
+}
@@ -0,0 +1,105 @@
+package yaml
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type unmarshalerConfig struct {
+	Name     string            `json:"name"`
+	Replicas int               `json:"replicas"`
+	Labels   map[string]string `json:"labels"`
+	Ports    []int             `json:"ports"`
+}
+
+const unmarshalerInput = `
+name: web
+replicas: 3
+labels:
+  app: web
+  tier: frontend
+ports: [80, 443]
+`
+
+func TestUnmarshaler(t *testing.T) {
+	var metrics []Metrics
+	u := NewUnmarshaler(Strict(), WithMetrics(func(m Metrics) {
+		metrics = append(metrics, m)
+	}))
+
+	for i := 0; i < 3; i++ {
+		var got, want unmarshalerConfig
+		if err := UnmarshalWithOptions([]byte(unmarshalerInput), &want); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := u.Unmarshal([]byte(unmarshalerInput), &got); err != nil {
+			t.Fatalf("decode %d: unexpected error: %v", i, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("decode %d = %+v, want %+v", i, got, want)
+		}
+	}
+
+	var got unmarshalerConfig
+	if err := u.Unmarshal([]byte("name: web\nreplica: 3\n"), &got); err == nil {
+		t.Errorf("expected an error for an unknown field")
+	}
+	if err := u.Unmarshal([]byte("name: web\n"), &got); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	var strictErrors []int
+	for _, m := range metrics {
+		strictErrors = append(strictErrors, m.StrictErrors)
+	}
+	if want := []int{0, 0, 0, 1, 0}; !reflect.DeepEqual(strictErrors, want) {
+		t.Errorf("strict errors per decode = %v, want %v", strictErrors, want)
+	}
+}
+
+func TestUnmarshalerReset(t *testing.T) {
+	u := NewUnmarshaler()
+	var v map[string]string
+	if err := u.Unmarshal([]byte("a: b\n"), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u.Reset()
+	if u.buf.Cap() == 0 {
+		t.Errorf("Reset released a small buffer")
+	}
+
+	large := "a: " + strings.Repeat("b", 2*maxRetainedBuffer) + "\n"
+	if err := u.Unmarshal([]byte(large), &v); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	u.Reset()
+	if u.buf.Cap() != 0 {
+		t.Errorf("Reset kept a buffer of %d bytes", u.buf.Cap())
+	}
+	if err := u.Unmarshal([]byte("a: c\n"), &v); err != nil || v["a"] != "c" {
+		t.Errorf("decode after Reset = %v, %v", v, err)
+	}
+}
+
+func BenchmarkUnmarshalWithOptions(b *testing.B) {
+	data := []byte(unmarshalerInput)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v unmarshalerConfig
+		if err := UnmarshalWithOptions(data, &v, Strict()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshaler(b *testing.B) {
+	data := []byte(unmarshalerInput)
+	u := NewUnmarshaler(Strict())
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		var v unmarshalerConfig
+		if err := u.Unmarshal(data, &v); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
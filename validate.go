@@ -0,0 +1,227 @@
+package yaml
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// Validator is implemented by types that check their own values. See
+// WithValidation.
+type Validator interface {
+	Validate() error
+}
+
+// FieldError is an error about a single field of a validated struct.
+// Validators can return it, or a FieldErrors, to have the error located at
+// the field rather than at the struct.
+type FieldError struct {
+	// Field is the key of the field in the document, which is usually its
+	// JSON name.
+	Field string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Err.Error()
+}
+
+// FieldErrors is a list of FieldErrors about the same struct.
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, fe := range e {
+		msgs[i] = fe.Error()
+	}
+	return strings.Join(msgs, "; ")
+}
+
+// WithValidation makes UnmarshalWithOptions validate every struct decoded
+// from the document, from the outermost to the innermost: it calls the
+// Validate method of structs implementing Validator, then fn, if not nil,
+// with a pointer to the struct. fn can adapt a validation library such as
+// github.com/go-playground/validator.
+//
+// The errors are reported together, in document order and prefixed with the
+// line of the offending struct or field, like the errors of a strict decode.
+// They are counted in Metrics.StrictErrors and passed to the Logger.
+func WithValidation(fn func(v interface{}) error) Option {
+	return func(o *options) {
+		o.validate = true
+		o.validateFn = fn
+	}
+}
+
+// validationError locates a validation error in the document.
+type validationError struct {
+	line int
+	msg  string
+}
+
+// validateDecoded validates the structs decoded from the YAML document y into
+// the value pointed to by o.
+func (c *converter) validateDecoded(y []byte, o interface{}) error {
+	var yamlObj interface{}
+	if err := yaml.Unmarshal(y, &yamlObj); err != nil {
+		return err
+	}
+	obj, err := defaultConverter.convertToJSONableObject(yamlObj, nil)
+	if err != nil {
+		return err
+	}
+	nodes, err := blockNodes(y, scanTokens(y))
+	if err != nil {
+		return err
+	}
+	lines := map[string]int{}
+	for _, n := range nodes {
+		if _, ok := lines[n.path]; !ok {
+			lines[n.path] = n.line
+		}
+	}
+
+	var errs []validationError
+	c.validateValue(reflect.ValueOf(o), obj, "", lines, &errs)
+	if len(errs) == 0 {
+		return nil
+	}
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].line < errs[j].line })
+	msgs := make([]string, len(errs))
+	for i, e := range errs {
+		msgs[i] = e.msg
+		if e.line > 0 {
+			msgs[i] = "line " + strconv.Itoa(e.line) + ": " + e.msg
+		}
+	}
+	c.strictErrors(msgs)
+	return fmt.Errorf("validation errors:\n  %s", strings.Join(msgs, "\n  "))
+}
+
+// validateValue validates the structs in v, which was decoded from obj, the
+// JSON-compatible form of the node at path.
+func (c *converter) validateValue(v reflect.Value, obj interface{}, path string, lines map[string]int, errs *[]validationError) {
+	if obj == nil {
+		return
+	}
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Struct:
+		c.validateStruct(v, path, lines, errs)
+		m, _ := obj.(map[string]interface{})
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		fields := c.fields(v.Type())
+		for _, k := range keys {
+			f := fields.lookup([]byte(c.mapKey(v.Type(), k)))
+			if f == nil {
+				continue
+			}
+			if fv, ok := fieldByIndex(v, f.index); ok {
+				c.validateValue(fv, m[k], joinPath(path, k), lines, errs)
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		s, _ := obj.([]interface{})
+		for i := 0; i < len(s) && i < v.Len(); i++ {
+			c.validateValue(v.Index(i), s[i], path+"["+strconv.Itoa(i)+"]", lines, errs)
+		}
+	case reflect.Map:
+		if v.Type().Key().Kind() != reflect.String {
+			return
+		}
+		m, _ := obj.(map[string]interface{})
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			mv := v.MapIndex(reflect.ValueOf(k).Convert(v.Type().Key()))
+			if mv.IsValid() {
+				c.validateValue(mv, m[k], joinPath(path, k), lines, errs)
+			}
+		}
+	}
+}
+
+// validateStruct runs the validators on the struct v found at path.
+func (c *converter) validateStruct(v reflect.Value, path string, lines map[string]int, errs *[]validationError) {
+	pv := reflect.New(v.Type())
+	if v.CanAddr() {
+		pv = v.Addr()
+	} else {
+		pv.Elem().Set(v)
+	}
+	if val, ok := pv.Interface().(Validator); ok {
+		addValidationError(val.Validate(), path, lines, errs)
+	}
+	if c.opts.validateFn != nil {
+		addValidationError(c.opts.validateFn(pv.Interface()), path, lines, errs)
+	}
+}
+
+// addValidationError appends err, returned by the validation of the struct
+// at path, to errs.
+func addValidationError(err error, path string, lines map[string]int, errs *[]validationError) {
+	switch e := err.(type) {
+	case nil:
+	case *FieldError:
+		addValidationError(FieldErrors{e}, path, lines, errs)
+	case FieldErrors:
+		for _, fe := range e {
+			p := joinPath(path, fe.Field)
+			*errs = append(*errs, validationError{line: locatePath(p, lines), msg: p + ": " + fe.Err.Error()})
+		}
+	default:
+		msg := err.Error()
+		if path != "" {
+			msg = path + ": " + msg
+		}
+		*errs = append(*errs, validationError{line: locatePath(path, lines), msg: msg})
+	}
+}
+
+// locatePath returns the line of the node at path, or of its closest
+// ancestor found in lines, or 0 if there is none.
+func locatePath(path string, lines map[string]int) int {
+	for path != "" {
+		if line, ok := lines[path]; ok {
+			return line
+		}
+		i := strings.LastIndexAny(path, ".[")
+		if i < 0 {
+			break
+		}
+		path = path[:i]
+	}
+	return 0
+}
+
+// fieldByIndex returns the field of the struct v at index, reporting false
+// if it is behind a nil embedded pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
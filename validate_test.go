@@ -0,0 +1,72 @@
+package yaml
+
+import (
+	"errors"
+	"testing"
+)
+
+type ValidatedPort struct {
+	Name string `json:"name"`
+	Port int    `json:"port"`
+}
+
+func (p *ValidatedPort) Validate() error {
+	if p.Port <= 0 {
+		return &FieldError{Field: "port", Err: errors.New("must be positive")}
+	}
+	return nil
+}
+
+type ValidatedService struct {
+	Name   string                   `json:"name"`
+	Ports  []ValidatedPort          `json:"ports"`
+	Named  map[string]ValidatedPort `json:"named"`
+	Backup *ValidatedPort           `json:"backup,omitempty"`
+}
+
+func TestWithValidation(t *testing.T) {
+	y := []byte(`name: web
+ports:
+- name: http
+  port: 80
+- name: https
+  port: -1
+named: {admin: {port: 0}}
+`)
+
+	var s ValidatedService
+	err := UnmarshalWithOptions(y, &s, WithValidation(nil))
+	want := "validation errors:\n  line 6: ports[1].port: must be positive\n  line 7: named.admin.port: must be positive"
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+
+	var structs int
+	err = UnmarshalWithOptions(y, &s, WithValidation(func(v interface{}) error {
+		structs++
+		if s, ok := v.(*ValidatedService); ok && len(s.Ports) > 1 {
+			return errors.New("too many ports")
+		}
+		return nil
+	}))
+	want = "validation errors:\n  too many ports\n  line 6: ports[1].port: must be positive\n  line 7: named.admin.port: must be positive"
+	if err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+	if structs != 4 {
+		t.Errorf("validated %d structs, want 4", structs)
+	}
+
+	var n int
+	err = UnmarshalWithOptions([]byte("name: web\nports:\n- port: 1\n"), &s,
+		WithValidation(nil), WithMetrics(func(m Metrics) { n = m.StrictErrors }))
+	if err != nil || n != 0 {
+		t.Errorf("got error %v and %d strict errors, want none", err, n)
+	}
+
+	err = UnmarshalWithOptions([]byte("name: web\nbackup:\n  port: 0\n"), &s,
+		WithValidation(nil), WithMetrics(func(m Metrics) { n = m.StrictErrors }))
+	if err == nil || n != 1 {
+		t.Errorf("got error %v and %d strict errors, want 1", err, n)
+	}
+}
@@ -0,0 +1,90 @@
+package yaml
+
+import (
+	"bytes"
+	"path"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+// DefaultVersionKeys are the key patterns KeepVersionStrings uses when given
+// none.
+var DefaultVersionKeys = []string{"version", "*Version", "*_version"}
+
+// KeepVersionStrings makes UnmarshalWithOptions, YAMLToJSONWithOptions,
+// UnmarshalAny and ParseDocument decode the unquoted numbers found at keys
+// matching one of patterns as strings holding their text, so that
+// "version: 1.20" gives "1.20" rather than the float 1.2, which the JSON
+// layer then writes as 1.2. Patterns are matched against the key alone, with
+// the syntax of path.Match, such as "*Version"; DefaultVersionKeys are used
+// if none are given.
+//
+// Only values of block mapping entries with a decimal point or an exponent
+// are affected, as integers keep their digits, and values with an explicit
+// tag are left alone. The values are decoded as if quoted, so the patterns
+// should only match keys of string or interface{} fields.
+func KeepVersionStrings(patterns ...string) Option {
+	if len(patterns) == 0 {
+		patterns = DefaultVersionKeys
+	}
+	return func(o *options) {
+		o.versionKeys = patterns
+	}
+}
+
+// quoteVersions quotes the plain float scalars of y that are the values of
+// block mapping keys matching one of patterns.
+func quoteVersions(y []byte, patterns []string) []byte {
+	tokens := scanTokens(y)
+	var out bytes.Buffer
+	last := 0
+	for i, t := range tokens {
+		if t.kind != keyToken || !matchAnyKey(patterns, keyText(y, t)) {
+			continue
+		}
+		j := i + 1
+		for j < len(tokens) && tokens[j].kind == anchorToken {
+			j++
+		}
+		if j >= len(tokens) {
+			continue
+		}
+		v := tokens[j]
+		s := string(y[v.start:v.end])
+		if v.kind != plainToken || v.multiline || !yamlFloat.MatchString(s) || !strings.ContainsAny(s, ".eE") {
+			continue
+		}
+		out.Write(y[last:v.start])
+		out.WriteByte('"')
+		out.WriteString(s)
+		out.WriteByte('"')
+		last = v.end
+	}
+	if last == 0 {
+		return y
+	}
+	out.Write(y[last:])
+	return out.Bytes()
+}
+
+// keyText returns the text of the key token t of y, unquoted.
+func keyText(y []byte, t token) string {
+	key := string(y[t.start:t.end])
+	if key != "" && (key[0] == '\'' || key[0] == '"') {
+		if err := yaml.Unmarshal(y[t.start:t.end], &key); err != nil {
+			return ""
+		}
+	}
+	return key
+}
+
+// matchAnyKey reports whether key matches one of patterns.
+func matchAnyKey(patterns []string, key string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,66 @@
+package yaml
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeepVersionStrings(t *testing.T) {
+	input := []byte(`version: 1.20
+cluster:
+  kubernetesVersion: &v 1.30
+  helm_version: 3.10 # pinned
+  "version": 2.0
+  replicas: 1.50
+  tagged_version: !!float 1.10
+  minVersion: 1
+  maxVersion: "1.40"
+  versions: [1.10, 1.20]
+`)
+
+	var v map[string]interface{}
+	if err := UnmarshalWithOptions(input, &v, KeepVersionStrings()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]interface{}{
+		"version": "1.20",
+		"cluster": map[string]interface{}{
+			"kubernetesVersion": "1.30",
+			"helm_version":      "3.10",
+			"version":           "2.0",
+			"replicas":          1.5,
+			"tagged_version":    1.1,
+			"minVersion":        float64(1),
+			"maxVersion":        "1.40",
+			"versions":          []interface{}{1.1, 1.2},
+		},
+	}
+	if !reflect.DeepEqual(v, want) {
+		t.Errorf("got %#v, want %#v", v, want)
+	}
+
+	j, err := YAMLToJSONWithOptions([]byte("apiVersion: v1\nspec:\n  release: 1.20\n  version: 1.20\n"), KeepVersionStrings("release"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := `{"apiVersion":"v1","spec":{"release":"1.20","version":1.2}}`; string(j) != want {
+		t.Errorf("got %s, want %s", j, want)
+	}
+
+	var s struct {
+		Version string `json:"version"`
+	}
+	if err := UnmarshalWithOptions([]byte("version: 1.20\n"), &s, KeepVersionStrings()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Version != "1.20" {
+		t.Errorf("got %q, want %q", s.Version, "1.20")
+	}
+
+	if err := UnmarshalWithOptions([]byte("version: 1.20\n"), &s); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.Version != "1.2" {
+		t.Errorf("without the option got %q, want %q", s.Version, "1.2")
+	}
+}
@@ -0,0 +1,161 @@
+package yaml
+
+import (
+	"bytes"
+	"reflect"
+	"sort"
+	"unicode/utf8"
+)
+
+// rewrapLines folds the long plain and quoted scalars of the YAML stream y,
+// which go-yaml produced, at width columns instead of go-yaml's 80, or keeps
+// them on a single line if width is negative. If the result would not
+// decode to the same content as y, y is returned unchanged.
+func rewrapLines(y []byte, width int) []byte {
+	out := unwrapLines(y)
+	if width > 0 {
+		out = foldLines(out, width)
+	}
+	if bytes.Equal(out, y) {
+		// Nothing changed, so there is nothing to check.
+		return y
+	}
+	want, err := yamlUnmarshalAll(y)
+	if err != nil {
+		return y
+	}
+	got, err := yamlUnmarshalAll(out)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		return y
+	}
+	return out
+}
+
+// unwrapLines undoes the line folding go-yaml applies to long plain and
+// quoted scalars in the YAML stream y, which it produced.
+func unwrapLines(y []byte) []byte {
+	var lineStarts []int
+	for i := 0; i < len(y); i++ {
+		if i == 0 || y[i-1] == '\n' {
+			lineStarts = append(lineStarts, i)
+		}
+	}
+	// lineOf returns the line holding offset.
+	lineOf := func(offset int) int {
+		return sort.Search(len(lineStarts), func(i int) bool { return lineStarts[i] > offset })
+	}
+
+	// Lines starting a node or document, and lines of block scalar content,
+	// stay as they are. Every other line after the first of a document
+	// continues a folded scalar from the line before.
+	keep := make([]bool, len(lineStarts)+2)
+	keep[1] = true
+	for _, t := range scanTokens(y) {
+		switch t.kind {
+		case keyToken, entryToken:
+			keep[t.line] = true
+		case documentToken:
+			keep[t.line] = true
+			if bytes.Equal(bytes.TrimSpace(y[t.start:lineEnd(y, t.start)]), []byte("---")) {
+				keep[t.line+1] = true
+			}
+		case blockScalarToken:
+			for l, end := t.line+1, lineOf(t.end); l <= end; l++ {
+				keep[l] = true
+			}
+		}
+	}
+
+	var out bytes.Buffer
+	last := 0
+	for i, start := range lineStarts {
+		if keep[i+1] {
+			continue
+		}
+		// Replace the preceding line break and this line's indentation with
+		// the single space it was folded from.
+		out.Write(y[last : start-1])
+		out.WriteByte(' ')
+		last = start
+		for last < len(y) && y[last] == ' ' {
+			last++
+		}
+	}
+	if last == 0 {
+		return y
+	}
+	out.Write(y[last:])
+	return out.Bytes()
+}
+
+// foldLines folds the plain and quoted scalars of the YAML stream y that are
+// mapping values, sequence entries or documents and run past width columns,
+// the way go-yaml does at 80 columns: a single space, between two other
+// characters, past the width is replaced by a line break and the
+// indentation of the scalar's continuation lines.
+func foldLines(y []byte, width int) []byte {
+	tokens := scanTokens(y)
+	keys := make(map[int]bool)
+	for _, t := range tokens {
+		if t.kind == keyToken {
+			keys[t.start] = true
+		}
+	}
+
+	var out bytes.Buffer
+	last := 0
+	// indent is the indentation of continuation lines, two columns past
+	// the last key or sequence entry.
+	indent := 2
+	for _, t := range tokens {
+		switch t.kind {
+		case keyToken, entryToken:
+			indent = t.column + 1
+			continue
+		case documentToken:
+			indent = 2
+			continue
+		case plainToken, quotedToken:
+		default:
+			continue
+		}
+		text := y[t.start:t.end]
+		if keys[t.start] || bytes.IndexByte(text, '\n') >= 0 {
+			continue
+		}
+		column := utf8.RuneCount(y[t.start-(t.column-1) : t.start])
+		if column+utf8.RuneCount(text) <= width {
+			continue
+		}
+		out.Write(y[last:t.start])
+		last = t.end
+		for i := 0; i < len(text); i++ {
+			c := text[i]
+			if c == ' ' && column > width && i > 0 && text[i-1] != ' ' && text[i-1] != '\\' &&
+				i+1 < len(text) && text[i+1] != ' ' {
+				out.WriteByte('\n')
+				out.Write(bytes.Repeat([]byte(" "), indent))
+				column = indent
+				continue
+			}
+			out.WriteByte(c)
+			if c&0xc0 != 0x80 {
+				column++
+			}
+		}
+	}
+	if last == 0 {
+		return y
+	}
+	out.Write(y[last:])
+	return out.Bytes()
+}
+
+// lineEnd returns the offset of the line break ending the line containing
+// offset, or len(y).
+func lineEnd(y []byte, offset int) int {
+	if i := bytes.IndexByte(y[offset:], '\n'); i >= 0 {
+		return offset + i
+	}
+	return len(y)
+}
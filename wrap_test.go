@@ -0,0 +1,141 @@
+package yaml
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDisableLineWrap(t *testing.T) {
+	long := strings.Repeat("word ", 25) + "end"
+	tests := []struct {
+		name  string
+		input interface{}
+		want  string
+	}{
+		{
+			name:  "plain",
+			input: map[string]interface{}{"a": long, "b": "short"},
+			want:  "a: " + long + "\nb: short\n",
+		},
+		{
+			name:  "single quoted",
+			input: map[string]interface{}{"a": map[string]interface{}{"b": []string{"'" + long}}},
+			want:  "a:\n  b:\n  - '''" + long + "'\n",
+		},
+		{
+			name:  "double quoted",
+			input: []string{"tab\t" + long},
+			want:  "- \"tab\\t" + long + "\"\n",
+		},
+		{
+			name:  "block scalar untouched",
+			input: map[string]string{"a": long + "\n" + long + "\n"},
+			want:  "a: |\n  " + long + "\n  " + long + "\n",
+		},
+		{
+			name:  "documents",
+			input: []string{long, long},
+			want:  long + "\n--- " + long + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := []Option{DisableLineWrap()}
+			if tt.name == "documents" {
+				opts = append(opts, JSONArrayAsDocuments())
+			}
+			got, err := MarshalWithOptions(tt.input, opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	wrapped, err := Marshal(map[string]string{"a": long})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(wrapped), "\n  word") {
+		t.Errorf("expected lines to be wrapped by default, got %q", wrapped)
+	}
+}
+
+func TestLineWidth(t *testing.T) {
+	long := strings.Repeat("word ", 12) + "end"
+	tests := []struct {
+		name  string
+		input interface{}
+		width int
+		want  string
+	}{
+		{
+			name:  "plain",
+			input: map[string]interface{}{"a": long, "b": "short"},
+			width: 30,
+			want:  "a: word word word word word word\n  word word word word word word\n  end\nb: short\n",
+		},
+		{
+			name:  "nested sequence entry",
+			input: map[string]interface{}{"a": []string{long}},
+			width: 40,
+			want:  "a:\n- word word word word word word word word\n  word word word word end\n",
+		},
+		{
+			name:  "double quoted",
+			input: []string{"tab\t" + long},
+			width: 30,
+			want:  "- \"tab\\tword word word word word\n  word word word word word word\n  word end\"\n",
+		},
+		{
+			name:  "keys stay on their line",
+			input: map[string]string{long: "x"},
+			width: 30,
+			want:  long + ": x\n",
+		},
+		{
+			name:  "no width",
+			input: map[string]string{"a": long + " " + long},
+			width: 0,
+			want:  "a: " + long + " " + long + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MarshalWithOptions(tt.input, LineWidth(tt.width))
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("MarshalWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	// At go-yaml's own width, the output is go-yaml's.
+	v := map[string]interface{}{"a": strings.Repeat(long+" ", 3), "b": []string{"'" + long + long}}
+	want, err := Marshal(v)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := MarshalWithOptions(v, LineWidth(80))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(got) != string(want) {
+		t.Errorf("MarshalWithOptions() = %q, want %q", got, want)
+	}
+}
+
+func BenchmarkDisableLineWrapBlockScalar(b *testing.B) {
+	// A 1 MB block scalar, whose lines all stay as they are.
+	v := map[string]string{"data": strings.Repeat(strings.Repeat("x", 99)+"\n", 10000)}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := MarshalWithOptions(v, DisableLineWrap()); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -6,20 +6,83 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"sort"
 	"strconv"
+	"time"
 
 	"gopkg.in/yaml.v2"
 )
 
 // Marshal marshals the object into JSON then converts JSON to YAML and returns the
 // YAML.
+//
+// Mapping keys are written sorted, except that the fields of a struct tagged
+// yamlorder:"n", where n is an integer weight, come first, by increasing
+// weight, so that generated manifests can start with apiVersion, kind and
+// metadata whatever the order of the struct's fields:
+//
+//	type Manifest struct {
+//		APIVersion string     `json:"apiVersion" yamlorder:"1"`
+//		Kind       string     `json:"kind" yamlorder:"2"`
+//		Metadata   ObjectMeta `json:"metadata" yamlorder:"3"`
+//		Data       Data       `json:"data"`
+//	}
 func Marshal(o interface{}) ([]byte, error) {
+	return MarshalWithOptions(o)
+}
+
+// MarshalWithOptions is like Marshal but honors the given options.
+func MarshalWithOptions(o interface{}, opts ...Option) ([]byte, error) {
+	opt := newOptions(opts...)
+	if opt.metrics == nil {
+		return marshalWithOptions(o, opt, opts)
+	}
+	start := time.Now()
+	y, err := marshalWithOptions(o, opt, opts)
+	opt.metrics(Metrics{
+		Operation: "Marshal",
+		Documents: len(splitDocuments(y)),
+		Bytes:     len(y),
+		Duration:  time.Since(start),
+		Err:       err,
+	})
+	return y, err
+}
+
+func marshalWithOptions(o interface{}, opt *options, opts []Option) ([]byte, error) {
 	j, err := json.Marshal(o)
 	if err != nil {
 		return nil, fmt.Errorf("error marshaling into JSON: %v", err)
 	}
 
-	y, err := JSONToYAML(j)
+	if opt.cipher != nil {
+		j, err = encryptJSON(j, reflect.TypeOf(o), opt)
+		if err != nil {
+			return nil, fmt.Errorf("error encrypting fields: %v", err)
+		}
+	}
+
+	c := &converter{fields: cachedStructFields, opts: opt}
+	if opt.fieldNameMapper != nil {
+		j, err = c.mapFieldNamesJSON(j, reflect.TypeOf(o))
+		if err != nil {
+			return nil, fmt.Errorf("error mapping field names: %v", err)
+		}
+	}
+
+	if t := reflect.TypeOf(o); cachedHasFieldOrder(t) {
+		var jsonObj interface{}
+		if err := yaml.Unmarshal(j, &jsonObj); err != nil {
+			return nil, fmt.Errorf("error converting JSON to YAML: %v", err)
+		}
+		y, err := jsonObjectToYAML(c.orderFields(jsonObj, t), opt)
+		if err != nil {
+			return nil, fmt.Errorf("error converting JSON to YAML: %v", err)
+		}
+		return y, nil
+	}
+
+	y, err := JSONToYAMLWithOptions(j, opts...)
 	if err != nil {
 		return nil, fmt.Errorf("error converting JSON to YAML: %v", err)
 	}
@@ -32,37 +95,211 @@ type JSONOpt func(*json.Decoder) *json.Decoder
 
 // Unmarshal converts YAML to JSON then uses JSON to unmarshal into an object,
 // optionally configuring the behavior of the JSON unmarshal.
+//
+// Besides the name given by its json tag, a struct field can be decoded from
+// the keys listed in a yamlalias tag:
+//
+//	Replicas int `json:"replicaCount" yamlalias:"replicas,count"`
+//
+// This lets a configuration format rename a key while still accepting the old
+// one. When several of these keys are present, the field's own key takes
+// precedence, then the aliases in the order listed; strict decoding rejects
+// the document instead. A key that names a field of its own is never an
+// alias. Decoding in strict mode with WithLogger logs each use of an alias
+// as deprecated. Marshaling always uses the field's own name.
 func Unmarshal(y []byte, o interface{}, opts ...JSONOpt) error {
-	return yamlUnmarshal(y, o, false, opts...)
+	return defaultConverter.yamlUnmarshal(y, o, false, opts...)
 }
 
 // UnmarshalStrict strictly converts YAML to JSON then uses JSON to unmarshal
 // into an object, optionally configuring the behavior of the JSON unmarshal.
 func UnmarshalStrict(y []byte, o interface{}, opts ...JSONOpt) error {
-	return yamlUnmarshal(y, o, true, append(opts, DisallowUnknownFields)...)
+	return defaultConverter.yamlUnmarshal(y, o, true, append(opts, DisallowUnknownFields)...)
+}
+
+// UnmarshalWithOptions is like Unmarshal but honors the given options.
+func UnmarshalWithOptions(y []byte, o interface{}, opts ...Option) error {
+	c := &converter{fields: cachedStructFields, opts: newOptions(opts...)}
+	return c.unmarshal(y, o)
+}
+
+// unmarshal decodes y into o according to the options of c, reporting
+// metrics if requested.
+func (c *converter) unmarshal(y []byte, o interface{}) error {
+	if c.opts.metrics == nil {
+		return c.opts.sourceError(c.unmarshalWithOptions(y, o))
+	}
+	start := time.Now()
+	err := c.opts.sourceError(c.unmarshalWithOptions(y, o))
+	c.opts.metrics(Metrics{
+		Operation:    "Unmarshal",
+		Documents:    1,
+		Bytes:        len(y),
+		Duration:     time.Since(start),
+		StrictErrors: c.opts.strictErrors,
+		Err:          err,
+	}.withStats(c.opts.stats))
+	return err
+}
+
+func (c *converter) unmarshalWithOptions(input []byte, o interface{}) error {
+	if c.opts.ranges != nil {
+		if err := c.opts.checkRanges(); err != nil {
+			return err
+		}
+	}
+	y, err := c.checkInput(input)
+	if err != nil {
+		return err
+	}
+	if c.opts.strict {
+		jsonOpts := c.opts.jsonOpts[:len(c.opts.jsonOpts):len(c.opts.jsonOpts)]
+		err = c.yamlUnmarshal(y, o, true, append(jsonOpts, DisallowUnknownFields)...)
+		if err != nil && c.opts.groupStrictErrors {
+			if serr := c.allStrictErrors(y, o); serr != nil {
+				err = serr
+			}
+		}
+	} else {
+		err = c.yamlUnmarshal(y, o, false, c.opts.jsonOpts...)
+	}
+	if err != nil {
+		return err
+	}
+	if err := c.checkDecoded(y, o); err != nil {
+		return err
+	}
+	if c.opts.ranges != nil {
+		recordRanges(y, c.opts.ranges)
+	}
+	return nil
+}
+
+// checkDecoded applies the options that inspect the value decoded from the
+// YAML document y into the value pointed to by o.
+func (c *converter) checkDecoded(y []byte, o interface{}) error {
+	if c.opts.logger != nil {
+		c.warnDeprecated(y, o)
+	}
+	if !c.opts.validate {
+		return nil
+	}
+	return c.validateDecoded(y, o)
+}
+
+// checkInput applies the options that check or fix up the YAML input of a
+// decode, returning the input to decode.
+func (c *converter) checkInput(y []byte) ([]byte, error) {
+	if c.opts.maxInputSize > 0 && len(y) > c.opts.maxInputSize {
+		return nil, fmt.Errorf("yaml: input of %d bytes exceeds the limit of %d bytes", len(y), c.opts.maxInputSize)
+	}
+	if c.opts.transcodeInput {
+		var err error
+		if y, err = transcode(y); err != nil {
+			return nil, err
+		}
+	}
+	if c.opts.tolerateTabs {
+		var lines []int
+		y, lines = ExpandTabIndentation(y, c.opts.tabWidth)
+		if len(lines) > 0 && c.opts.tabWarn != nil {
+			c.opts.tabWarn(lines)
+		}
+		if len(lines) > 0 && c.opts.logger != nil {
+			c.opts.logger.Info("expanded tab indentation", "lines", lines)
+		}
+	}
+	if c.opts.disallowDuplicateAnchors {
+		if err := checkDuplicateAnchors(y); err != nil {
+			return nil, err
+		}
+	}
+	if c.opts.disallowAliases || c.opts.disallowMergeKeys || c.opts.disallowCustomTags {
+		if err := checkSafeTokens(y, c.opts); err != nil {
+			return nil, err
+		}
+	}
+	if c.opts.singleDocument {
+		if err := checkSingleDocument(y); err != nil {
+			return nil, err
+		}
+	}
+	if c.opts.disallowControlCharacters {
+		if err := checkControlCharacters(y); err != nil {
+			return nil, err
+		}
+	}
+	if c.opts.detectTruncation {
+		if err := checkTruncation(y); err != nil {
+			return nil, err
+		}
+	}
+	if c.opts.disallowTrailingContent {
+		if err := checkTrailingContent(y); err != nil {
+			return nil, err
+		}
+	}
+	if len(c.opts.versionKeys) > 0 {
+		y = quoteVersions(y, c.opts.versionKeys)
+	}
+	return y, nil
 }
 
 // yamlUnmarshal unmarshals the given YAML byte stream into the given interface,
 // optionally performing the unmarshalling strictly
-func yamlUnmarshal(y []byte, o interface{}, strict bool, opts ...JSONOpt) error {
+func (c *converter) yamlUnmarshal(y []byte, o interface{}, strict bool, opts ...JSONOpt) error {
 	vo := reflect.ValueOf(o)
 	unmarshalFn := yaml.Unmarshal
 	if strict {
-		unmarshalFn = yaml.UnmarshalStrict
+		sc := *c
+		sc.strict = true
+		c = &sc
+		unmarshalFn = func(y []byte, o interface{}) error {
+			err := yaml.UnmarshalStrict(y, o)
+			if te, ok := err.(*yaml.TypeError); ok {
+				c.strictErrors(te.Errors)
+			}
+			return err
+		}
 	}
-	j, err := yamlToJSON(y, &vo, unmarshalFn)
+	j, err := c.yamlToJSON(y, &vo, unmarshalFn)
 	if err != nil {
 		return fmt.Errorf("error converting YAML to JSON: %v", err)
 	}
+	return c.decodeJSON(j, o, strict, opts...)
+}
 
-	err = jsonUnmarshal(bytes.NewReader(j), o, opts...)
+// decodeJSON unmarshals the JSON form j of a YAML document into o.
+func (c *converter) decodeJSON(j []byte, o interface{}, strict bool, opts ...JSONOpt) error {
+	err := jsonUnmarshal(bytes.NewReader(j), o, opts...)
 	if err != nil {
+		if strict && len(c.unknown) > 0 {
+			sort.Strings(c.unknown)
+			errs := make([]string, len(c.unknown))
+			for i, key := range c.unknown {
+				errs[i] = fmt.Sprintf("unknown field %q", key)
+			}
+			c.strictErrors(errs)
+		}
 		return fmt.Errorf("error unmarshaling JSON: %v", err)
 	}
 
 	return nil
 }
 
+// strictErrors records the problems found by a strict decode.
+func (c *converter) strictErrors(errs []string) {
+	if c.opts == nil {
+		return
+	}
+	c.opts.strictErrors += len(errs)
+	if c.opts.logger != nil {
+		for _, e := range errs {
+			logStrictError(c.opts.logger, e)
+		}
+	}
+}
+
 // jsonUnmarshal unmarshals the JSON byte stream from the given reader into the
 // object, optionally applying decoder options prior to decoding.  We are not
 // using json.Unmarshal directly as we want the chance to pass in non-default
@@ -79,7 +316,17 @@ func jsonUnmarshal(r io.Reader, o interface{}, opts ...JSONOpt) error {
 }
 
 // JSONToYAML Converts JSON to YAML.
+//
+// Keys that YAML would read as something other than a string, such as "1.20",
+// "08" or "true", are quoted so that they survive a conversion back to JSON.
 func JSONToYAML(j []byte) ([]byte, error) {
+	return JSONToYAMLWithOptions(j)
+}
+
+// JSONToYAMLWithOptions is like JSONToYAML but honors the given options.
+func JSONToYAMLWithOptions(j []byte, opts ...Option) ([]byte, error) {
+	o := newOptions(opts...)
+
 	// Convert the JSON to an object.
 	var jsonObj interface{}
 	// We are using yaml.Unmarshal here (instead of json.Unmarshal) because the
@@ -91,9 +338,55 @@ func JSONToYAML(j []byte) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	return jsonObjectToYAML(jsonObj, o)
+}
 
-	// Marshal this object into YAML.
-	return yaml.Marshal(jsonObj)
+// jsonObjectToYAML converts jsonObj, a JSON document decoded by go-yaml, to
+// YAML according to the options o.
+func jsonObjectToYAML(jsonObj interface{}, o *options) ([]byte, error) {
+	var y []byte
+	var err error
+	if arr, ok := jsonObj.([]interface{}); ok && o.jsonArrayAsDocuments {
+		y, err = marshalDocuments(arr)
+	} else {
+		// Marshal this object into YAML.
+		y, err = yaml.Marshal(jsonObj)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if o.lineWidth != 0 {
+		y = rewrapLines(y, o.lineWidth)
+	}
+	if o.emitNullAsEmpty {
+		y = emptyNulls(y)
+	}
+	if o.floatFormat != 0 || o.integerDigits {
+		y = rewriteFloats(y, o.formatYAMLFloat)
+	}
+	return y, nil
+}
+
+// marshalDocuments marshals each element of objs as its own YAML document,
+// separating consecutive documents with "---".
+func marshalDocuments(objs []interface{}) ([]byte, error) {
+	// The go-yaml encoder refuses to close a stream it never started, so an
+	// empty list is handled up front.
+	if len(objs) == 0 {
+		return []byte{}, nil
+	}
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	for _, obj := range objs {
+		if err := enc.Encode(obj); err != nil {
+			return nil, err
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // YAMLToJSON converts YAML to JSON. Since JSON is a subset of YAML,
@@ -109,39 +402,250 @@ func JSONToYAML(j []byte) ([]byte, error) {
 //
 // For strict decoding of YAML, use YAMLToJSONStrict.
 func YAMLToJSON(y []byte) ([]byte, error) {
-	return yamlToJSON(y, nil, yaml.Unmarshal)
+	return defaultConverter.yamlToJSON(y, nil, yaml.Unmarshal)
 }
 
 // YAMLToJSONStrict is like YAMLToJSON but enables strict YAML decoding,
 // returning an error on any duplicate field names.
 func YAMLToJSONStrict(y []byte) ([]byte, error) {
-	return yamlToJSON(y, nil, yaml.UnmarshalStrict)
+	return defaultConverter.yamlToJSON(y, nil, yaml.UnmarshalStrict)
 }
 
-func yamlToJSON(y []byte, jsonTarget *reflect.Value, yamlUnmarshal func([]byte, interface{}) error) ([]byte, error) {
+// YAMLToJSONWithOptions is like YAMLToJSON but honors the given options.
+func YAMLToJSONWithOptions(y []byte, opts ...Option) ([]byte, error) {
+	c := &converter{fields: cachedStructFields, opts: newOptions(opts...)}
+	if c.opts.metrics == nil {
+		return c.yamlToJSONWithOptions(y)
+	}
+	start := time.Now()
+	j, err := c.yamlToJSONWithOptions(y)
+	c.opts.metrics(Metrics{
+		Operation:    "YAMLToJSON",
+		Documents:    1,
+		Bytes:        len(y),
+		Duration:     time.Since(start),
+		StrictErrors: c.opts.strictErrors,
+		Err:          err,
+	}.withStats(c.opts.stats))
+	return j, err
+}
+
+// yamlToJSONWithOptions converts y to JSON according to the options of c,
+// without a Go target.
+func (c *converter) yamlToJSONWithOptions(y []byte) ([]byte, error) {
+	y, err := c.checkInput(y)
+	if err != nil {
+		return nil, c.opts.sourceError(err)
+	}
+	unmarshalFn := yaml.Unmarshal
+	if c.opts.strict {
+		unmarshalFn = yaml.UnmarshalStrict
+	}
+	if c.opts.orderedMaps {
+		// The order is taken from the same parse as the content.
+		unmarshal := unmarshalFn
+		unmarshalFn = func(y []byte, o interface{}) error {
+			var d orderedDocument
+			if err := unmarshal(y, &d); err != nil {
+				return err
+			}
+			*o.(*interface{}) = d.content
+			c.order = d.order
+			return nil
+		}
+	}
+	j, err := c.yamlToJSON(y, nil, unmarshalFn)
+	if err != nil {
+		return nil, c.opts.sourceError(err)
+	}
+	if c.opts.jsonIndent != "" {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, j, "", c.opts.jsonIndent); err != nil {
+			return nil, err
+		}
+		j = buf.Bytes()
+	}
+	return j, nil
+}
+
+// converter converts YAML objects into JSON-compatible ones.
+type converter struct {
+	// fields returns the index of the JSON fields of the struct type t.
+	fields func(t reflect.Type) *structFields
+	// opts holds the settings of the *WithOptions functions, if any.
+	opts *options
+	// schema, if set, decides which numbers and booleans become strings
+	// when there is no Go target.
+	schema *jsonSchema
+	// order, if set, holds the document decoded with yaml.MapSlices, whose
+	// key order the JSON output follows.
+	order interface{}
+	// buf, if set, receives the JSON form of decoded documents, so that its
+	// storage is reused from one decode to the next.
+	buf *bytes.Buffer
+	// strict is set while decoding strictly, to reject keys that set the
+	// same field.
+	strict bool
+	// unknown collects, while decoding strictly, the keys that match no
+	// field of their struct, which the JSON decoder then rejects.
+	unknown []string
+	// texts, if set, holds the document decoded with the source text of
+	// each scalar, for the parsers of ParseScalars.
+	texts interface{}
+}
+
+// defaultConverter is used by the package-level conversion functions.
+var defaultConverter = &converter{fields: cachedStructFields}
+
+func (c *converter) yamlToJSON(y []byte, jsonTarget *reflect.Value, yamlUnmarshal func([]byte, interface{}) error) ([]byte, error) {
 	// Convert the YAML to an object.
 	var yamlObj interface{}
-	err := yamlUnmarshal(y, &yamlObj)
+	var err error
+	if jsonTarget != nil && c.opts != nil && len(c.opts.scalarParsers) > 0 {
+		var d scalarDocument
+		err = yamlUnmarshal(y, &d)
+		yamlObj, c.texts = d.content, d.texts
+	} else {
+		err = yamlUnmarshal(y, &yamlObj)
+	}
 	if err != nil {
 		return nil, err
 	}
+	if c.opts != nil && c.opts.metrics != nil {
+		c.opts.stats = statsOf(y, yamlObj)
+	}
+	return c.objectToJSON(yamlObj, jsonTarget)
+}
 
+// objectToJSON converts the object yamlObj, as decoded by go-yaml, to JSON
+// for decoding into jsonTarget, if not nil.
+func (c *converter) objectToJSON(yamlObj interface{}, jsonTarget *reflect.Value) ([]byte, error) {
+	if c.opts != nil && c.opts.maxDepth > 0 {
+		if err := checkDepth(yamlObj, c.opts.maxDepth); err != nil {
+			return nil, err
+		}
+	}
 	// YAML objects are not completely compatible with JSON objects (e.g. you
 	// can have non-string keys in YAML). So, convert the YAML-compatible object
 	// to a JSON-compatible object, failing with an error if irrecoverable
 	// incompatibilties happen along the way.
-	jsonObj, err := convertToJSONableObject(yamlObj, jsonTarget)
+	jsonObj, err := c.convert(yamlObj, c.texts, jsonTarget)
 	if err != nil {
 		return nil, err
 	}
 
+	if c.schema != nil {
+		jsonObj = c.schema.coerce(jsonObj, []map[string]interface{}{c.schema.root})
+	}
+
+	if c.opts != nil && c.opts.cipher != nil {
+		var t reflect.Type
+		if jsonTarget != nil {
+			t = jsonTarget.Type()
+		}
+		jsonObj, err = decryptObject(jsonObj, t, c.opts)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.opts != nil && c.opts.applyDefaults && jsonTarget != nil {
+		t := jsonTarget.Type()
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if jsonObj == nil && t.Kind() == reflect.Struct {
+			// An empty document gets the defaults of every field.
+			jsonObj = map[string]interface{}{}
+		}
+		jsonObj, err = c.applyDefaults(jsonObj, t)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if c.opts != nil && c.opts.floatFormat != 0 {
+		jsonObj = formatJSONFloats(jsonObj, c.opts.floatFormat)
+	}
+
 	// Convert this object to JSON and return the data.
+	if c.order != nil {
+		var buf bytes.Buffer
+		if err := writeOrderedJSON(&buf, jsonObj, c.order); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	}
+	if c.buf != nil {
+		c.buf.Reset()
+		if err := json.NewEncoder(c.buf).Encode(jsonObj); err != nil {
+			return nil, err
+		}
+		return c.buf.Bytes(), nil
+	}
 	return json.Marshal(jsonObj)
 }
 
-func convertToJSONableObject(yamlObj interface{}, jsonTarget *reflect.Value) (interface{}, error) {
+// keyToString converts a map key decoded by go-yaml into a JSON object key.
+// It reports false for key types that cannot be converted.
+//
+// From my reading of go-yaml v2 (specifically the resolve function), keys can
+// only have the types string, int, int64, float64, binary (unsupported), or
+// null (unsupported).
+func keyToString(k interface{}) (string, bool) {
+	switch typedKey := k.(type) {
+	case string:
+		return typedKey, true
+	case int:
+		return strconv.Itoa(typedKey), true
+	case int64:
+		// go-yaml will only return an int64 as a key if the system
+		// architecture is 32-bit and the key's value is between 32-bit
+		// and 64-bit. Otherwise the key type will simply be int.
+		return strconv.FormatInt(typedKey, 10), true
+	case float64:
+		// Stolen from go-yaml to use the same conversion to string as
+		// the go-yaml library uses to convert float to string when
+		// Marshaling.
+		s := strconv.FormatFloat(typedKey, 'g', -1, 32)
+		switch s {
+		case "+Inf":
+			s = ".inf"
+		case "-Inf":
+			s = "-.inf"
+		case "NaN":
+			s = ".nan"
+		}
+		return s, true
+	case bool:
+		if typedKey {
+			return "true", true
+		}
+		return "false", true
+	}
+	return "", false
+}
+
+func (c *converter) convertToJSONableObject(yamlObj interface{}, jsonTarget *reflect.Value) (interface{}, error) {
+	return c.convert(yamlObj, nil, jsonTarget)
+}
+
+// convert is convertToJSONableObject, with texts, if not nil, holding
+// yamlObj with the source text of each scalar.
+func (c *converter) convert(yamlObj, texts interface{}, jsonTarget *reflect.Value) (interface{}, error) {
 	var err error
 
+	if jsonTarget != nil && c.opts != nil && len(c.opts.scalarParsers) > 0 {
+		if v, ok, err := c.parseScalar(yamlObj, texts, jsonTarget.Type()); ok {
+			return v, err
+		}
+	}
+	if jsonTarget != nil && c.opts != nil && len(c.opts.enums) > 0 {
+		if yamlObj, err = c.checkEnum(yamlObj, jsonTarget.Type()); err != nil {
+			return nil, err
+		}
+	}
+
 	// Resolve jsonTarget to a concrete value (i.e. not a pointer or an
 	// interface). We pass decodingNull as false because we're not actually
 	// decoding into the value, we're just checking if the ultimate target is a
@@ -166,45 +670,20 @@ func convertToJSONableObject(yamlObj interface{}, jsonTarget *reflect.Value) (in
 	case map[interface{}]interface{}:
 		// JSON does not support arbitrary keys in a map, so we must convert
 		// these keys to strings.
-		//
-		// From my reading of go-yaml v2 (specifically the resolve function),
-		// keys can only have the types string, int, int64, float64, binary
-		// (unsupported), or null (unsupported).
 		strMap := make(map[string]interface{})
+		textMap, _ := texts.(map[interface{}]interface{})
+		// resolved holds the fields set by the keys of a mapping decoded
+		// into a struct.
+		var resolved map[string]resolvedKey
+		if jsonTarget != nil && jsonTarget.Kind() == reflect.Struct {
+			if resolved, err = c.resolveStructKeys(jsonTarget.Type(), typedYAMLObj); err != nil {
+				return nil, err
+			}
+		}
 		for k, v := range typedYAMLObj {
 			// Resolve the key to a string first.
-			var keyString string
-			switch typedKey := k.(type) {
-			case string:
-				keyString = typedKey
-			case int:
-				keyString = strconv.Itoa(typedKey)
-			case int64:
-				// go-yaml will only return an int64 as a key if the system
-				// architecture is 32-bit and the key's value is between 32-bit
-				// and 64-bit. Otherwise the key type will simply be int.
-				keyString = strconv.FormatInt(typedKey, 10)
-			case float64:
-				// Stolen from go-yaml to use the same conversion to string as
-				// the go-yaml library uses to convert float to string when
-				// Marshaling.
-				s := strconv.FormatFloat(typedKey, 'g', -1, 32)
-				switch s {
-				case "+Inf":
-					s = ".inf"
-				case "-Inf":
-					s = "-.inf"
-				case "NaN":
-					s = ".nan"
-				}
-				keyString = s
-			case bool:
-				if typedKey {
-					keyString = "true"
-				} else {
-					keyString = "false"
-				}
-			default:
+			keyString, ok := keyToString(k)
+			if !ok {
 				return nil, fmt.Errorf("Unsupported map key of type: %s, key: %+#v, value: %+#v",
 					reflect.TypeOf(k), k, v)
 			}
@@ -217,43 +696,48 @@ func convertToJSONableObject(yamlObj interface{}, jsonTarget *reflect.Value) (in
 			if jsonTarget != nil {
 				t := *jsonTarget
 				if t.Kind() == reflect.Struct {
-					keyBytes := []byte(keyString)
-					// Find the field that the JSON library would use.
-					var f *field
-					fields := cachedTypeFields(t.Type())
-					for i := range fields {
-						ff := &fields[i]
-						if bytes.Equal(ff.nameBytes, keyBytes) {
-							f = ff
-							break
-						}
-						// Do case-insensitive comparison.
-						if f == nil && ff.equalFold(ff.nameBytes, keyBytes) {
-							f = ff
-						}
+					r := resolved[keyString]
+					if r.shadowedBy != "" {
+						continue
 					}
+					keyString = r.name
+					// Find the field that the JSON library would use.
+					f := c.fields(t.Type()).lookup([]byte(keyString))
 					if f != nil {
 						// Find the reflect.Value of the most preferential
-						// struct field.
-						jtf := t.Field(f.index[0])
-						strMap[keyString], err = convertToJSONableObject(v, &jtf)
+						// struct field, which may be promoted from an
+						// embedded struct.
+						jtf, ok := fieldByIndex(t, f.index)
+						if !ok {
+							// It is behind a nil embedded pointer.
+							jtf = reflect.Zero(t.Type().FieldByIndex(f.index).Type)
+						}
+						strMap[keyString], err = c.convert(v, textMap[k], &jtf)
 						if err != nil {
 							return nil, err
 						}
 						continue
 					}
+					// The JSON library drops the values of unknown fields,
+					// so don't spend time converting them. The key is kept
+					// for strict decoding to reject.
+					strMap[keyString] = nil
+					if c.strict {
+						c.unknown = append(c.unknown, keyString)
+					}
+					continue
 				} else if t.Kind() == reflect.Map {
 					// Create a zero value of the map's element type to use as
 					// the JSON target.
 					jtv := reflect.Zero(t.Type().Elem())
-					strMap[keyString], err = convertToJSONableObject(v, &jtv)
+					strMap[keyString], err = c.convert(v, textMap[k], &jtv)
 					if err != nil {
 						return nil, err
 					}
 					continue
 				}
 			}
-			strMap[keyString], err = convertToJSONableObject(v, nil)
+			strMap[keyString], err = c.convert(v, textMap[k], nil)
 			if err != nil {
 				return nil, err
 			}
@@ -280,8 +764,13 @@ func convertToJSONableObject(yamlObj interface{}, jsonTarget *reflect.Value) (in
 
 		// Make and use a new array.
 		arr := make([]interface{}, len(typedYAMLObj))
+		textArr, _ := texts.([]interface{})
 		for i, v := range typedYAMLObj {
-			arr[i], err = convertToJSONableObject(v, jsonSliceElemValue)
+			var text interface{}
+			if i < len(textArr) {
+				text = textArr[i]
+			}
+			arr[i], err = c.convert(v, text, jsonSliceElemValue)
 			if err != nil {
 				return nil, err
 			}
@@ -291,26 +780,7 @@ func convertToJSONableObject(yamlObj interface{}, jsonTarget *reflect.Value) (in
 		// If the target type is a string and the YAML type is a number,
 		// convert the YAML type to a string.
 		if jsonTarget != nil && (*jsonTarget).Kind() == reflect.String {
-			// Based on my reading of go-yaml, it may return int, int64,
-			// float64, or uint64.
-			var s string
-			switch typedVal := typedYAMLObj.(type) {
-			case int:
-				s = strconv.FormatInt(int64(typedVal), 10)
-			case int64:
-				s = strconv.FormatInt(typedVal, 10)
-			case float64:
-				s = strconv.FormatFloat(typedVal, 'g', -1, 32)
-			case uint64:
-				s = strconv.FormatUint(typedVal, 10)
-			case bool:
-				if typedVal {
-					s = "true"
-				} else {
-					s = "false"
-				}
-			}
-			if len(s) > 0 {
+			if s := scalarString(typedYAMLObj); len(s) > 0 {
 				yamlObj = interface{}(s)
 			}
 		}
@@ -318,6 +788,29 @@ func convertToJSONableObject(yamlObj interface{}, jsonTarget *reflect.Value) (in
 	}
 }
 
+// scalarString returns the string a number or boolean decoded by go-yaml
+// becomes when its target is a string, or "" for other values.
+func scalarString(v interface{}) string {
+	// Based on my reading of go-yaml, it may return int, int64,
+	// float64, or uint64.
+	switch typedVal := v.(type) {
+	case int:
+		return strconv.FormatInt(int64(typedVal), 10)
+	case int64:
+		return strconv.FormatInt(typedVal, 10)
+	case float64:
+		return strconv.FormatFloat(typedVal, 'g', -1, 32)
+	case uint64:
+		return strconv.FormatUint(typedVal, 10)
+	case bool:
+		if typedVal {
+			return "true"
+		}
+		return "false"
+	}
+	return ""
+}
+
 // JSONObjectToYAMLObject converts an in-memory JSON object into a YAML in-memory MapSlice,
 // without going through a byte representation. A nil or empty map[string]interface{} input is
 // converted to an empty map, i.e. yaml.MapSlice(nil).
@@ -378,3 +871,59 @@ func jsonToYAMLValue(j interface{}) interface{} {
 	}
 	return j
 }
+
+// YAMLObjectToJSONObject is the inverse of JSONObjectToYAMLObject: it converts
+// an in-memory YAML MapSlice into a JSON object, without going through a byte
+// representation. A nil or empty MapSlice is converted to an empty map.
+//
+// yaml.MapSlice and map[interface{}]interface{} become map[string]interface{},
+// with keys converted to strings the same way YAMLToJSON converts them.
+// interface{} slices stay interface{} slices.
+//
+// Numbers keep their type, so int64 and uint64 values do not lose precision as
+// they would in a round trip through JSON bytes, which decodes them as float64.
+//
+// string, bool and any other types are unchanged.
+func YAMLObjectToJSONObject(y yaml.MapSlice) (map[string]interface{}, error) {
+	ret := make(map[string]interface{}, len(y))
+	for _, item := range y {
+		k, ok := keyToString(item.Key)
+		if !ok {
+			return nil, fmt.Errorf("Unsupported map key of type: %s, key: %+#v",
+				reflect.TypeOf(item.Key), item.Key)
+		}
+		v, err := yamlToJSONValue(item.Value)
+		if err != nil {
+			return nil, err
+		}
+		ret[k] = v
+	}
+	return ret, nil
+}
+
+func yamlToJSONValue(y interface{}) (interface{}, error) {
+	switch y := y.(type) {
+	case yaml.MapSlice:
+		return YAMLObjectToJSONObject(y)
+	case map[interface{}]interface{}:
+		ms := make(yaml.MapSlice, 0, len(y))
+		for k, v := range y {
+			ms = append(ms, yaml.MapItem{Key: k, Value: v})
+		}
+		return YAMLObjectToJSONObject(ms)
+	case []interface{}:
+		if y == nil {
+			return interface{}(nil), nil
+		}
+		ret := make([]interface{}, len(y))
+		for i := range y {
+			v, err := yamlToJSONValue(y[i])
+			if err != nil {
+				return nil, err
+			}
+			ret[i] = v
+		}
+		return ret, nil
+	}
+	return y, nil
+}
@@ -1,12 +1,14 @@
 package yaml
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"math"
 	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/davecgh/go-spew/spew"
@@ -37,6 +39,38 @@ func TestMarshal(t *testing.T) {
 	}
 }
 
+func TestMarshalAsDocuments(t *testing.T) {
+	objs := []map[string]interface{}{
+		{"kind": "Namespace", "metadata": map[string]string{"name": "a"}},
+		{"kind": "ConfigMap", "data": map[string][]int{"x": {1, 2}}},
+	}
+	e := "kind: Namespace\nmetadata:\n  name: a\n---\ndata:\n  x:\n  - 1\n  - 2\nkind: ConfigMap\n"
+
+	y, err := MarshalWithOptions(objs, AsDocuments())
+	if err != nil {
+		t.Fatalf("error marshaling YAML: %v", err)
+	}
+	if string(y) != e {
+		t.Errorf("marshal YAML was unsuccessful, expected: %#v, got: %#v", e, string(y))
+	}
+
+	y, err = MarshalWithOptions([]MarshalTest{}, AsDocuments())
+	if err != nil {
+		t.Fatalf("error marshaling YAML: %v", err)
+	}
+	if len(y) != 0 {
+		t.Errorf("expected an empty stream, got: %#v", string(y))
+	}
+
+	y, err = MarshalWithOptions(MarshalTest{A: "a"}, AsDocuments())
+	if err != nil {
+		t.Fatalf("error marshaling YAML: %v", err)
+	}
+	if e := "A: a\nB: 0\nC: 0\n"; string(y) != e {
+		t.Errorf("marshal YAML was unsuccessful, expected: %#v, got: %#v", e, string(y))
+	}
+}
+
 type UnmarshalString struct {
 	A    string
 	True string
@@ -123,6 +157,61 @@ func unmarshal(t *testing.T, y []byte, s, e interface{}, opts ...JSONOpt) {
 	}
 }
 
+func TestUnmarshalSkipsUnknownFields(t *testing.T) {
+	// The unknown field holds a key JSON cannot represent, which would fail
+	// the conversion if the field were not skipped.
+	y := []byte("a: x\nunknown:\n  ~: v\n")
+	s := UnmarshalString{}
+	if err := Unmarshal(y, &s); err != nil {
+		t.Fatalf("error unmarshaling YAML: %v", err)
+	}
+	if s.A != "x" {
+		t.Errorf("expected A to be %q, got %q", "x", s.A)
+	}
+	if err := UnmarshalStrict(y, &s); err == nil || !strings.Contains(err.Error(), `unknown field "unknown"`) {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestUnmarshalPromotedFields(t *testing.T) {
+	type Sub struct {
+		N int `json:"num"`
+	}
+	type Inner struct {
+		X Sub `json:"x"`
+	}
+	type InnerPtr struct {
+		Z Sub `json:"z"`
+	}
+	type Outer struct {
+		Inner
+		*InnerPtr
+	}
+	var o Outer
+	if err := Unmarshal([]byte("x: {num: 1}\nz: {num: 2}\n"), &o); err != nil {
+		t.Fatalf("error unmarshaling YAML: %v", err)
+	}
+	if o.X.N != 1 || o.InnerPtr == nil || o.Z.N != 2 {
+		t.Errorf("got %+v, want promoted fields set", o)
+	}
+}
+
+func BenchmarkUnmarshalUnknownFields(b *testing.B) {
+	var buf bytes.Buffer
+	buf.WriteString("a: x\nunknown:\n")
+	for i := 0; i < 1000; i++ {
+		fmt.Fprintf(&buf, "  k%d: {a: [1, 2, 3], b: {c: d}}\n", i)
+	}
+	y := buf.Bytes()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var s UnmarshalString
+		if err := Unmarshal(y, &s); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
 func TestUnmarshalStrict(t *testing.T) {
 	y := []byte("a: 1")
 	s1 := UnmarshalString{}
@@ -277,6 +366,131 @@ func TestJSONToYAML(t *testing.T) {
 	runCases(t, RunTypeJSONToYAML, cases)
 }
 
+func TestJSONToYAMLWithOptions(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		want  string
+	}{
+		{
+			name:  "array as sequence by default",
+			input: `[{"a":1},{"b":2}]`,
+			want:  "- a: 1\n- b: 2\n",
+		},
+		{
+			name:  "array as documents",
+			input: `[{"kind":"A"},{"kind":"B"},"c"]`,
+			opts:  []Option{JSONArrayAsDocuments()},
+			want:  "kind: A\n---\nkind: B\n--- c\n",
+		},
+		{
+			name:  "nested arrays stay sequences",
+			input: `[[1,2]]`,
+			opts:  []Option{JSONArrayAsDocuments()},
+			want:  "- 1\n- 2\n",
+		},
+		{
+			name:  "empty array yields no documents",
+			input: `[]`,
+			opts:  []Option{JSONArrayAsDocuments()},
+			want:  "",
+		},
+		{
+			name:  "objects are unaffected",
+			input: `{"a":[1]}`,
+			opts:  []Option{JSONArrayAsDocuments()},
+			want:  "a:\n- 1\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JSONToYAMLWithOptions([]byte(tt.input), tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("JSONToYAMLWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAMLToJSONIndent(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		opts  []Option
+		want  string
+	}{
+		{
+			name:  "compact by default",
+			input: "b: [1, 2]\na: {}\n",
+			want:  `{"a":{},"b":[1,2]}`,
+		},
+		{
+			name:  "two spaces",
+			input: "b: [1, 2]\na: {}\n",
+			opts:  []Option{IndentJSON("  ")},
+			want:  "{\n  \"a\": {},\n  \"b\": [\n    1,\n    2\n  ]\n}",
+		},
+		{
+			name:  "tabs in document order",
+			input: "b: x\na: z\n",
+			opts:  []Option{IndentJSON("\t"), OrderedMaps()},
+			want:  "{\n\t\"b\": \"x\",\n\t\"a\": \"z\"\n}",
+		},
+		{
+			name:  "scalar",
+			input: "x\n",
+			opts:  []Option{IndentJSON("  ")},
+			want:  `"x"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := YAMLToJSONWithOptions([]byte(tt.input), tt.opts...)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("YAMLToJSONWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestJSONToYAMLQuotesNumericKeys checks that keys that a YAML 1.1 or 1.2
+// parser would read as numbers are quoted, so that they survive a conversion
+// back to JSON unchanged.
+func TestJSONToYAMLQuotesNumericKeys(t *testing.T) {
+	keys := []string{
+		"1.20", "08", "0777", "0x1F", "0o17", "0b101", "1_000", "1e3", "1E+3",
+		"+1", "-1", ".5", "1.", "1:20", "190:20:30.15", ".inf", "-.Inf", ".NaN",
+		"true", "off", "null", "~",
+	}
+	for _, k := range keys {
+		j, err := json.Marshal(map[string]string{k: "v"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		y, err := JSONToYAML(j)
+		if err != nil {
+			t.Fatalf("JSONToYAML(%s): unexpected error: %v", j, err)
+		}
+		if y[0] != '"' && y[0] != '\'' {
+			t.Errorf("JSONToYAML(%s) = %q, want a quoted key", j, y)
+		}
+		got, err := YAMLToJSON(y)
+		if err != nil {
+			t.Fatalf("YAMLToJSON(%q): unexpected error: %v", y, err)
+		}
+		if string(got) != string(j) {
+			t.Errorf("round trip of %s gave %s", j, got)
+		}
+	}
+}
+
 func TestYAMLToJSON(t *testing.T) {
 	cases := []Case{
 		{
@@ -544,3 +758,53 @@ func sortMapSlicesInPlace(x interface{}) {
 		})
 	}
 }
+
+func TestYAMLObjectToJSONObject(t *testing.T) {
+	const bigUint64 = ((uint64(1) << 63) + 500) / 1000 * 1000
+
+	input := yaml.MapSlice{
+		{Key: "nil"},
+		{Key: "empty map", Value: yaml.MapSlice(nil)},
+		{Key: "slice", Value: []interface{}{"foo", yaml.MapSlice{{Key: 1, Value: true}}}},
+		{Key: "map", Value: map[interface{}]interface{}{"a": int64(1) << 62, 1.5: "b"}},
+		{Key: 2, Value: bigUint64},
+		{Key: false, Value: float64(42.1)},
+	}
+	expected := map[string]interface{}{
+		"nil":       nil,
+		"empty map": map[string]interface{}{},
+		"slice":     []interface{}{"foo", map[string]interface{}{"1": true}},
+		"map":       map[string]interface{}{"a": int64(1) << 62, "1.5": "b"},
+		"2":         bigUint64,
+		"false":     float64(42.1),
+	}
+	got, err := YAMLObjectToJSONObject(input)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("YAMLObjectToJSONObject() = %v, want %v", spew.Sdump(got), spew.Sdump(expected))
+	}
+
+	// Round trip through JSONObjectToYAMLObject.
+	back, err := YAMLObjectToJSONObject(JSONObjectToYAMLObject(expected))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	expected["map"].(map[string]interface{})["a"] = intOrInt64(int64(1) << 62)
+	if !reflect.DeepEqual(back, expected) {
+		t.Errorf("round trip = %v, want %v", spew.Sdump(back), spew.Sdump(expected))
+	}
+
+	_, err = YAMLObjectToJSONObject(yaml.MapSlice{{Key: []interface{}{1}, Value: "x"}})
+	if err == nil {
+		t.Error("expected error for unsupported key")
+	}
+}
+
+func intOrInt64(i64 int64) interface{} {
+	if i := int(i64); i64 == int64(i) {
+		return i
+	}
+	return i64
+}
@@ -0,0 +1,160 @@
+// Package yamltest provides helpers for tests that produce or consume YAML.
+//
+// Comparisons are semantic: documents are equal if they decode to the same
+// JSON-compatible values, regardless of key order, quoting, indentation or
+// comments. When they differ, the failure shows a line diff of the
+// documents in a normalized form, with keys sorted.
+package yamltest
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// UpdateEnv is the environment variable that makes AssertGolden and
+// AssertRoundTrip rewrite golden files instead of comparing against them,
+// when set to a non-empty value.
+const UpdateEnv = "UPDATE_GOLDEN"
+
+// AssertEqualYAML fails the test if the YAML streams want and got do not
+// hold the same documents.
+func AssertEqualYAML(t testing.TB, want, got []byte) {
+	t.Helper()
+	if diff, err := Diff(want, got); err != nil {
+		t.Errorf("cannot compare YAML: %v", err)
+	} else if diff != "" {
+		t.Errorf("YAML mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// AssertGolden compares got with the contents of the golden file at path
+// like AssertEqualYAML. If the environment variable named by UpdateEnv is
+// set, it writes got to the file instead.
+func AssertGolden(t testing.TB, path string, got []byte) {
+	t.Helper()
+	if os.Getenv(UpdateEnv) != "" {
+		if err := ioutil.WriteFile(path, got, 0644); err != nil {
+			t.Fatalf("cannot update golden file: %v", err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read golden file (set %s=1 to create it): %v", UpdateEnv, err)
+	}
+	AssertEqualYAML(t, want, got)
+}
+
+// AssertRoundTrip marshals v and compares the result with the golden file at
+// path like AssertGolden. It then unmarshals the golden file into a new value
+// of the type of v and fails the test if it differs from v.
+func AssertRoundTrip(t testing.TB, path string, v interface{}) {
+	t.Helper()
+	got, err := yaml.Marshal(v)
+	if err != nil {
+		t.Fatalf("cannot marshal %T: %v", v, err)
+	}
+	AssertGolden(t, path, got)
+
+	golden, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatalf("cannot read golden file: %v", err)
+	}
+	rt := reflect.TypeOf(v)
+	out := reflect.New(rt)
+	if err := yaml.UnmarshalStrict(golden, out.Interface()); err != nil {
+		t.Fatalf("cannot unmarshal golden file into %s: %v", rt, err)
+	}
+	if !reflect.DeepEqual(out.Elem().Interface(), v) {
+		t.Errorf("round trip through %s changed the value:\nwant %#v\ngot  %#v", path, v, out.Elem().Interface())
+	}
+}
+
+// Diff returns a line diff of the YAML streams want and got in normalized
+// form, or "" if they hold the same documents.
+func Diff(want, got []byte) (string, error) {
+	w, err := normalize(want)
+	if err != nil {
+		return "", fmt.Errorf("want: %v", err)
+	}
+	g, err := normalize(got)
+	if err != nil {
+		return "", fmt.Errorf("got: %v", err)
+	}
+	if w == g {
+		return "", nil
+	}
+	return diffLines(strings.Split(w, "\n"), strings.Split(g, "\n")), nil
+}
+
+// normalize returns the documents of the YAML stream y re-emitted with
+// sorted keys and uniform formatting.
+func normalize(y []byte) (string, error) {
+	docs, err := yaml.SplitDocuments(y)
+	if err != nil {
+		return "", err
+	}
+	var out bytes.Buffer
+	for i, doc := range docs {
+		j, err := yaml.YAMLToJSON(doc)
+		if err != nil {
+			return "", fmt.Errorf("document %d: %v", i+1, err)
+		}
+		n, err := yaml.JSONToYAML(j)
+		if err != nil {
+			return "", fmt.Errorf("document %d: %v", i+1, err)
+		}
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+		out.Write(n)
+	}
+	return strings.TrimSuffix(out.String(), "\n"), nil
+}
+
+// diffLines returns a unified-style listing of the lines of a and b, with
+// removed lines prefixed by "-" and added lines by "+".
+func diffLines(a, b []string) string {
+	// lcs[i][j] is the length of the longest common subsequence of a[i:]
+	// and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var out strings.Builder
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			out.WriteString("  " + a[i] + "\n")
+			i++
+			j++
+		case j == len(b) || i < len(a) && lcs[i+1][j] >= lcs[i][j+1]:
+			out.WriteString("- " + a[i] + "\n")
+			i++
+		default:
+			out.WriteString("+ " + b[j] + "\n")
+			j++
+		}
+	}
+	return out.String()
+}
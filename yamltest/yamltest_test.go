@@ -0,0 +1,84 @@
+package yamltest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// recorder is a testing.TB that records failures instead of reporting them.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertEqualYAML(t *testing.T) {
+	r := &recorder{TB: t}
+	AssertEqualYAML(r, []byte("b: 1\na: [x, 'z']\n"), []byte("# comment\na:\n- x\n- z\nb: 1\n"))
+	if len(r.failures) != 0 {
+		t.Errorf("unexpected failures: %q", r.failures)
+	}
+
+	r = &recorder{TB: t}
+	AssertEqualYAML(r, []byte("a: 1\nb: 2\nc: 3\n---\nd: 4\n"), []byte("c: 3\na: 1\nb: 5\n---\nd: 4\n"))
+	want := "YAML mismatch (-want +got):\n  a: 1\n- b: 2\n+ b: 5\n  c: 3\n  ---\n  d: 4\n"
+	if len(r.failures) != 1 || r.failures[0] != want {
+		t.Errorf("got failures %q, want %q", r.failures, want)
+	}
+
+	r = &recorder{TB: t}
+	AssertEqualYAML(r, []byte("a: 1\n"), []byte("a: ["))
+	if len(r.failures) != 1 || !strings.HasPrefix(r.failures[0], "cannot compare YAML: got: ") {
+		t.Errorf("got failures %q, want a parse failure", r.failures)
+	}
+}
+
+type config struct {
+	Name  string   `json:"name"`
+	Ports []int    `json:"ports"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+func TestAssertRoundTrip(t *testing.T) {
+	dir, err := ioutil.TempDir("", "yamltest")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.yaml")
+	v := config{Name: "web", Ports: []int{80, 443}}
+
+	r := &recorder{TB: t}
+	AssertRoundTrip(r, path, v)
+	if len(r.failures) == 0 || !strings.Contains(r.failures[0], "set "+UpdateEnv+"=1") {
+		t.Errorf("got failures %q, want a missing golden file failure", r.failures)
+	}
+
+	os.Setenv(UpdateEnv, "1")
+	AssertRoundTrip(t, path, v)
+	os.Unsetenv(UpdateEnv)
+	AssertRoundTrip(t, path, v)
+
+	if err := ioutil.WriteFile(path, []byte("name: web\nports: [80, 443]\ntags: []\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	r = &recorder{TB: t}
+	AssertRoundTrip(r, path, v)
+	if len(r.failures) != 2 || !strings.HasPrefix(r.failures[0], "YAML mismatch") ||
+		!strings.HasPrefix(r.failures[1], "round trip through") {
+		t.Errorf("got failures %q, want a mismatch and a round trip failure", r.failures)
+	}
+}